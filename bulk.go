@@ -0,0 +1,74 @@
+package zkwasm
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultBulkConcurrency is the number of concurrent requests of GetTasks.
+const DefaultBulkConcurrency = 8
+
+// GetTasks looks up many tasks concurrently, with at most
+// WithBulkConcurrency requests in flight. Every distinct id ends up in
+// either tasks or errs; errs matches ErrTaskNotFound with errors.Is for
+// tasks the service does not know, and holds the request error otherwise.
+func (h *ZkWasmServiceHelper) GetTasks(ctx context.Context, ids []string) (tasks map[string]*Task, errs map[string]error) {
+	ctx, span := h.startSpan(ctx, "zkwasm.GetTasks", attribute.Int("zkwasm.tasks.requested", len(ids)))
+	defer span.End()
+
+	tasks = make(map[string]*Task, len(ids))
+	errs = map[string]error{}
+
+	queue := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < min(h.bulkConcurrency, len(ids)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				task, err := h.QueryTask(ctx, id)
+				mu.Lock()
+				if err != nil {
+					errs[id] = err
+				} else {
+					tasks[id] = task
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		select {
+		case queue <- id:
+		case <-ctx.Done():
+			mu.Lock()
+			errs[id] = ctx.Err()
+			mu.Unlock()
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	span.SetAttributes(
+		attribute.Int("zkwasm.tasks.count", len(tasks)),
+		attribute.Int("zkwasm.tasks.errors", len(errs)),
+	)
+	h.logger.DebugContext(ctx, "got tasks",
+		slog.Int("requested", len(seen)),
+		slog.Int("found", len(tasks)),
+		slog.Int("errors", len(errs)),
+	)
+
+	return tasks, errs
+}
@@ -0,0 +1,87 @@
+package zkwasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetTasks(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var data []*Task
+		switch id := r.URL.Query().Get("id"); id {
+		case "broken":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"error":"bad id"}`))
+			return
+		case "missing":
+		default:
+			data = append(data, &Task{ID: id, Status: TaskStatusDone})
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": true,
+			"result":  map[string]any{"data": data, "total": len(data)},
+		})
+	}, WithBulkConcurrency(3), WithRetryPolicy(NoRetry()))
+
+	ids := []string{"missing", "broken", "1"}
+	for i := 2; i <= 20; i++ {
+		ids = append(ids, fmt.Sprint(i))
+	}
+	ids = append(ids, "1")
+
+	tasks, errs := h.GetTasks(context.Background(), ids)
+	if len(tasks) != 20 {
+		t.Errorf("found %d tasks, want 20", len(tasks))
+	}
+	if task := tasks["7"]; task == nil || task.ID != "7" {
+		t.Errorf(`tasks["7"] = %+v`, task)
+	}
+	if len(errs) != 2 {
+		t.Errorf("errs = %v, want 2 errors", errs)
+	}
+	if !errors.Is(errs["missing"], ErrTaskNotFound) {
+		t.Errorf(`errs["missing"] = %v, want %v`, errs["missing"], ErrTaskNotFound)
+	}
+	var apiErr *APIError
+	if err := errs["broken"]; !errors.As(err, &apiErr) || errors.Is(err, ErrTaskNotFound) {
+		t.Errorf(`errs["broken"] = %v, want a request error`, err)
+	}
+	if m := maxInFlight.Load(); m > 3 {
+		t.Errorf("%d concurrent requests, want at most 3", m)
+	}
+}
+
+func TestGetTasksCanceled(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent with a canceled context")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tasks, errs := h.GetTasks(ctx, []string{"1", "2", "3"})
+	if len(tasks) != 0 || len(errs) != 3 {
+		t.Fatalf("GetTasks() = %v, %v", tasks, errs)
+	}
+	for id, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("errs[%s] = %v, want %v", id, err, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrNoVerifierContract     = errors.New("no verifier contract for chain")
+	ErrUnsupportedCircuitSize = errors.New("unsupported circuit size")
+)
+
+type ServiceConfig struct {
+	Version         string           `json:"version"`
+	ReceiverAddress string           `json:"receiver_address"`
+	CircuitSizes    []int64          `json:"circuit_sizes"`
+	TaskFees        TaskFeeConfig    `json:"task_fee_list"`
+	ChainInfoList   []ChainInfo      `json:"chain_info_list"`
+	Deployments     []DeploymentInfo `json:"deployments"`
+}
+
+type TaskFeeConfig struct {
+	SetupFee      string `json:"setup_fee"`
+	ProveFee      string `json:"prove_fee"`
+	AutoSubmitFee string `json:"auto_submit_fee,omitempty"`
+}
+
+type ChainInfo struct {
+	ChainID          int64  `json:"chain_id"`
+	ChainName        string `json:"chain_name"`
+	BlockExplorerURL string `json:"block_explorer_url,omitempty"`
+}
+
+// DeploymentInfo is the verifier contract deployed on a chain.
+type DeploymentInfo struct {
+	ChainID int64  `json:"chain_id"`
+	Address string `json:"address"`
+}
+
+func (c *ServiceConfig) VerifierContract(chainID int64) (common.Address, bool) {
+	for _, d := range c.Deployments {
+		if d.ChainID == chainID && common.IsHexAddress(d.Address) {
+			return common.HexToAddress(d.Address), true
+		}
+	}
+
+	return common.Address{}, false
+}
+
+func (c *ServiceConfig) ChainIDs() []int64 {
+	ids := make([]int64, 0, len(c.ChainInfoList))
+	for _, ci := range c.ChainInfoList {
+		ids = append(ids, ci.ChainID)
+	}
+
+	return ids
+}
+
+// SupportsCircuitSize reports whether size is accepted by the service. It is
+// true when the service does not advertise its circuit sizes.
+func (c *ServiceConfig) SupportsCircuitSize(size int64) bool {
+	return len(c.CircuitSizes) == 0 || slices.Contains(c.CircuitSizes, size)
+}
+
+// QueryConfig fetches the service configuration and caches it in the helper.
+func (h *ZkWasmServiceHelper) QueryConfig(ctx context.Context) (*ServiceConfig, error) {
+	ctx, span := h.startSpan(ctx, "zkwasm.QueryConfig")
+	defer span.End()
+
+	config, err := doRequest[*ServiceConfig](ctx, h, &apiCall{
+		method:   http.MethodGet,
+		endpoint: endpointConfig,
+	})
+	if err == nil && config == nil {
+		err = errEmptyResult(endpointConfig)
+	}
+	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "query config failed", slog.Any("error", err))
+		return nil, err
+	}
+
+	h.logger.DebugContext(ctx, "queried config",
+		slog.String("version", config.Version),
+		slog.Any("chain_ids", config.ChainIDs()),
+	)
+
+	h.configMu.Lock()
+	h.config = config
+	h.configMu.Unlock()
+
+	return config, nil
+}
+
+// CachedConfig returns the configuration fetched by the last QueryConfig, or
+// nil.
+func (h *ZkWasmServiceHelper) CachedConfig() *ServiceConfig {
+	h.configMu.Lock()
+	defer h.configMu.Unlock()
+
+	return h.config
+}
+
+func (h *ZkWasmServiceHelper) cachedOrQueryConfig(ctx context.Context) (*ServiceConfig, error) {
+	if c := h.CachedConfig(); c != nil {
+		return c, nil
+	}
+
+	return h.QueryConfig(ctx)
+}
+
+// Ping checks that the primary zkWasm endpoint answers the config API with a
+// service response. Unlike QueryConfig it neither retries nor fails over.
+func (h *ZkWasmServiceHelper) Ping(ctx context.Context) error {
+	ctx, span := h.startSpan(ctx, "zkwasm.Ping")
+	defer span.End()
+
+	config, err := doOnce[*ServiceConfig](ctx, h, h.endpoints.primary(), &apiCall{
+		method:   http.MethodGet,
+		endpoint: endpointConfig,
+	}, 1)
+	if err == nil && config == nil {
+		err = errEmptyResult(endpointConfig)
+	}
+	if err != nil {
+		recordError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+// verifyContract returns the contract passed to New, or the one the service
+// advertises for chainID.
+func (h *ZkWasmServiceHelper) verifyContract(ctx context.Context, chainID int64) (common.Address, error) {
+	if h.verifyContractAddress != (common.Address{}) {
+		return h.verifyContractAddress, nil
+	}
+
+	config, err := h.cachedOrQueryConfig(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	address, ok := config.VerifierContract(chainID)
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w %d", ErrNoVerifierContract, chainID)
+	}
+
+	return address, nil
+}
+
+// validateCircuitSize checks size against the cached configuration, it does
+// not fetch it.
+func (h *ZkWasmServiceHelper) validateCircuitSize(size int64) error {
+	config := h.CachedConfig()
+	if config == nil || config.SupportsCircuitSize(size) {
+		return nil
+	}
+
+	sizes := make([]string, len(config.CircuitSizes))
+	for i, s := range config.CircuitSizes {
+		sizes[i] = fmt.Sprint(s)
+	}
+
+	return fmt.Errorf("%w %d, supported: %s", ErrUnsupportedCircuitSize, size, strings.Join(sizes, ", "))
+}
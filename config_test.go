@@ -0,0 +1,96 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testConfig = `{
+	"success": true,
+	"result": {
+		"version": "1.2.0",
+		"receiver_address": "0x582867abf63EbfA5803327a960AC381C2E01d67b",
+		"circuit_sizes": [18, 20, 22],
+		"task_fee_list": {"setup_fee": "5000000000000000", "prove_fee": "1000000000000000"},
+		"chain_info_list": [{"chain_id": 11155111, "chain_name": "Sepolia"}],
+		"deployments": [{"chain_id": 11155111, "address": "0x9D48Dce80682108864F1FB719229DCd0C45E51D7"}]
+	}
+}`
+
+func TestQueryConfig(t *testing.T) {
+	var setupCalls int
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case endpointConfig:
+			w.Write([]byte(testConfig))
+		case endpointSetup:
+			setupCalls++
+			w.Write([]byte(`{"success":true,"result":{"id":"image-id","md5":"md5"}}`))
+		}
+	}, WithConfigDiscovery())
+
+	ctx := context.Background()
+	config := h.CachedConfig()
+	if config == nil {
+		t.Fatal("CachedConfig() = nil after WithConfigDiscovery")
+	}
+	if config.Version != "1.2.0" || config.TaskFees.ProveFee != "1000000000000000" {
+		t.Errorf("config = %+v", config)
+	}
+
+	address, ok := config.VerifierContract(11155111)
+	if !ok || address != common.HexToAddress("0x9D48Dce80682108864F1FB719229DCd0C45E51D7") {
+		t.Errorf("VerifierContract(11155111) = %s, %v", address, ok)
+	}
+	if _, ok := config.VerifierContract(1); ok {
+		t.Errorf("VerifierContract(1) found a contract")
+	}
+
+	if err := h.Ping(ctx); err != nil {
+		t.Errorf("Ping() err = %v", err)
+	}
+
+	_, err := h.AddNewWasmImage(ctx, &AddImageParams{Name: "image.wasm", Image: []byte("wasm"), CircuitSize: 24})
+	if !errors.Is(err, ErrUnsupportedCircuitSize) {
+		t.Errorf("AddNewWasmImage() err = %v, want %v", err, ErrUnsupportedCircuitSize)
+	}
+	if setupCalls != 0 {
+		t.Errorf("image with an unsupported circuit size was sent")
+	}
+
+	if _, err := h.AddNewWasmImage(ctx, &AddImageParams{Name: "image.wasm", Image: []byte("wasm")}); err != nil {
+		t.Errorf("AddNewWasmImage() err = %v", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{"ok", http.StatusOK, testConfig, false},
+		{"gateway 404", http.StatusNotFound, `<html>404 Not Found</html>`, true},
+		{"not a service response", http.StatusOK, `<html>It works!</html>`, true},
+		{"server error", http.StatusServiceUnavailable, `{"success":false,"error":"maintenance"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != endpointConfig {
+					t.Errorf("Ping requested %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, WithRetryPolicy(NoRetry()))
+			if err := h.Ping(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Ping() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
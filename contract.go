@@ -2,15 +2,18 @@ package zkwasm
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"math/big"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
+	verifyGasLimit = uint64(1500000)
+
 	verifyABIJSON = `
 [
   {
@@ -53,9 +56,29 @@ const (
 func (h *ZkWasmServiceHelper) ContractVerify(ctx context.Context, txData []byte,
 	proof []byte, verifyInstance []byte, aux []byte, instances []byte) (string, error) {
 
+	ctx, span := h.startSpan(ctx, "zkwasm.ContractVerify")
+	defer span.End()
+
+	if h.ethClient == nil {
+		recordError(span, ErrNoEthClient)
+		return "", ErrNoEthClient
+	}
+	if h.signer == nil {
+		recordError(span, ErrNoSigner)
+		return "", ErrNoSigner
+	}
+
+	logger := h.logger.With(slog.String("from", h.userAddress.Hex()))
+	fail := func(step string, err error) (string, error) {
+		recordError(span, err)
+		h.metrics.IncContractVerify(false)
+		logger.ErrorContext(ctx, "contract verify failed", slog.String("step", step), slog.Any("error", err))
+		return "", err
+	}
+
 	verifyABI, err := abi.JSON(strings.NewReader(verifyABIJSON))
 	if err != nil {
-		return "", err
+		return fail("abi", err)
 	}
 
 	data, err := verifyABI.Pack("verify",
@@ -66,51 +89,72 @@ func (h *ZkWasmServiceHelper) ContractVerify(ctx context.Context, txData []byte,
 		[][]*big.Int{ByteSliceToBigIntSlice(instances, true)},
 	)
 	if err != nil {
-		fmt.Println(err)
-		return "", err
+		return fail("pack", err)
 	}
 
 	chainID, err := h.ethClient.ChainID(ctx)
 	if err != nil {
-		return "", err
+		return fail("chain_id", err)
 	}
+	logger = logger.With(slog.String("chain_id", chainID.String()))
+	span.SetAttributes(attrChainID.Int64(chainID.Int64()))
+
+	contract, err := h.verifyContract(ctx, chainID.Int64())
+	if err != nil {
+		return fail("contract", err)
+	}
+	logger = logger.With(slog.String("contract", contract.Hex()))
+	span.SetAttributes(attribute.String("eth.contract", contract.Hex()))
 
 	nonce, err := h.ethClient.PendingNonceAt(ctx, h.userAddress)
 	if err != nil {
-		return "", err
+		return fail("nonce", err)
 	}
 
 	gasPrice, err := h.ethClient.SuggestGasPrice(ctx)
 	if err != nil {
-		return "", err
+		return fail("gas_price", err)
 	}
 
 	gasTipCap, err := h.ethClient.SuggestGasTipCap(ctx)
 	if err != nil {
-		return "", err
+		return fail("gas_tip_cap", err)
 	}
 
-	signTx, err := types.SignNewTx(h.wallet, types.NewLondonSigner(chainID), &types.DynamicFeeTx{
+	logger.DebugContext(ctx, "prepared verify transaction",
+		slog.Uint64("nonce", nonce),
+		slog.String("gas_fee_cap", gasPrice.String()),
+		slog.String("gas_tip_cap", gasTipCap.String()),
+		slog.Uint64("gas", verifyGasLimit),
+	)
+
+	signTx, err := h.signer.SignTx(ctx, types.NewTx(&types.DynamicFeeTx{
 		ChainID:   chainID,
 		Nonce:     nonce,
 		GasTipCap: gasTipCap,
 		GasFeeCap: gasPrice,
-		Gas:       uint64(1500000),
-		To:        &h.verifyContractAddress,
+		Gas:       verifyGasLimit,
+		To:        &contract,
 		Value:     big.NewInt(0),
 		Data:      data,
-	})
+	}), chainID)
 	if err != nil {
-		fmt.Println(err)
-		return "", err
+		return fail("sign", err)
 	}
 
-	err = h.ethClient.SendTransaction(ctx, signTx)
+	span.SetAttributes(attrTxHash.String(signTx.Hash().Hex()))
 
+	err = h.ethClient.SendTransaction(ctx, signTx)
 	if err != nil {
-		fmt.Println(err)
-		return "", err
+		logger = logger.With(slog.String("tx_hash", signTx.Hash().Hex()))
+		return fail("send", err)
 	}
 
+	h.metrics.IncContractVerify(true)
+	logger.InfoContext(ctx, "sent verify transaction",
+		slog.String("tx_hash", signTx.Hash().Hex()),
+		slog.Uint64("nonce", nonce),
+	)
+
 	return signTx.Hash().Hex(), nil
 }
@@ -1,8 +1,42 @@
 package zkwasm
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 type Response[T any] struct {
-	Success bool `json:"success"`
-	Result  T    `json:"result"`
+	Success bool           `json:"success"`
+	Result  T              `json:"result"`
+	Error   *ResponseError `json:"error,omitempty"`
+}
+
+type ResponseError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// UnmarshalJSON accepts both a plain string and a {code, message} object,
+// the service has used both shapes.
+func (e *ResponseError) UnmarshalJSON(data []byte) error {
+	var msg string
+	if err := json.Unmarshal(data, &msg); err == nil {
+		e.Message = msg
+		return nil
+	}
+
+	var obj struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	e.Code = strings.Trim(string(obj.Code), `"`)
+	e.Message = obj.Message
+
+	return nil
 }
 
 type PaginationResult[T any] struct {
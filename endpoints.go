@@ -0,0 +1,238 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultFailureThreshold = 3
+	// DefaultEndpointCooldown is how long an unhealthy endpoint is skipped
+	// before reads try it again.
+	DefaultEndpointCooldown = 30 * time.Second
+)
+
+type EndpointStatus struct {
+	URL                 string
+	Primary             bool
+	Healthy             bool
+	ConsecutiveFailures int
+
+	// retryAt is when an unhealthy endpoint may be tried again.
+	retryAt time.Time
+}
+
+// usable reports whether reads may be sent to e.
+func (e *EndpointStatus) usable(now time.Time) bool {
+	return e.Healthy || !now.Before(e.retryAt)
+}
+
+// endpointPool tracks the health of the configured zkWasm endpoints. Reads go
+// to the first healthy endpoint and fail over to the others, writes always go
+// to the primary, the first endpoint. An unhealthy endpoint is tried again
+// after the cool-down, so the primary takes reads back once it recovers even
+// without health checks.
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*EndpointStatus
+	threshold int
+	cooldown  time.Duration
+}
+
+func newEndpointPool(urls []string, threshold int) *endpointPool {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+
+	p := &endpointPool{threshold: threshold, cooldown: DefaultEndpointCooldown}
+	for i, u := range urls {
+		p.endpoints = append(p.endpoints, &EndpointStatus{
+			URL:     strings.TrimSuffix(u, "/"),
+			Primary: i == 0,
+			Healthy: true,
+		})
+	}
+
+	return p
+}
+
+func (p *endpointPool) primary() string {
+	return p.endpoints[0].URL
+}
+
+// pick returns the endpoint for the next attempt of a call. Reads skip the
+// endpoints in tried unless all of them have been tried.
+func (p *endpointPool) pick(write bool, tried map[string]bool) string {
+	if write || len(p.endpoints) == 1 {
+		return p.primary()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range p.endpoints {
+		if e.usable(now) && !tried[e.URL] {
+			return e.URL
+		}
+	}
+	for _, e := range p.endpoints {
+		if !tried[e.URL] {
+			return e.URL
+		}
+	}
+	for _, e := range p.endpoints {
+		if e.usable(now) {
+			return e.URL
+		}
+	}
+
+	return p.primary()
+}
+
+// untried reports whether some endpoint other than those in tried can still
+// serve a read, in which case it can be attempted without backing off.
+func (p *endpointPool) untried(write bool, tried map[string]bool) bool {
+	if write {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range p.endpoints {
+		if e.usable(now) && !tried[e.URL] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *endpointPool) report(url string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range p.endpoints {
+		if e.URL != url {
+			continue
+		}
+
+		if ok {
+			e.ConsecutiveFailures = 0
+			e.Healthy = true
+			return
+		}
+
+		e.ConsecutiveFailures++
+		if e.ConsecutiveFailures >= p.threshold {
+			e.Healthy = false
+			e.retryAt = time.Now().Add(p.cooldown)
+		}
+		return
+	}
+}
+
+func (p *endpointPool) status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		status[i] = *e
+	}
+
+	return status
+}
+
+// isEndpointFailure reports whether err says something about the health of
+// the endpoint rather than about the request.
+func isEndpointFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsServerError()
+	}
+
+	return !errors.Is(err, ErrTransferAborted) && !errors.Is(err, ErrResponseTooLarge)
+}
+
+// Endpoints returns the health of the configured zkWasm endpoints, the primary
+// first.
+func (h *ZkWasmServiceHelper) Endpoints() []EndpointStatus {
+	return h.endpoints.status()
+}
+
+func (h *ZkWasmServiceHelper) startHealthCheck(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.stopHealthCheck = cancel
+	h.healthCheckDone = make(chan struct{})
+
+	go func() {
+		defer close(h.healthCheckDone)
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+
+			for _, e := range h.endpoints.status() {
+				probeCtx, cancel := context.WithTimeout(ctx, interval)
+				h.endpoints.report(e.URL, h.probe(probeCtx, e.URL) == nil)
+				cancel()
+			}
+		}
+	}()
+}
+
+// probe requests a single task from url, bypassing retries and middlewares.
+// Any answer below 500 means the endpoint is up; Ping is stricter.
+func (h *ZkWasmServiceHelper) probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+endpointTasks+"?total=1", nil)
+	if err != nil {
+		return err
+	}
+	for k, vs := range h.headers {
+		req.Header[k] = vs
+	}
+	if h.userAgent != "" {
+		req.Header.Set("User-Agent", h.userAgent)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &APIError{StatusCode: resp.StatusCode, Endpoint: endpointTasks, Message: "health check failed"}
+	}
+
+	return nil
+}
+
+// Close stops the health checks and closes the eth client.
+func (h *ZkWasmServiceHelper) Close() {
+	if h.stopHealthCheck != nil {
+		h.stopHealthCheck()
+		<-h.healthCheckDone
+	}
+
+	if h.ethClient != nil {
+		h.ethClient.Close()
+	}
+}
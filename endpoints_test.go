@@ -0,0 +1,136 @@
+package zkwasm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpointFailover(t *testing.T) {
+	var primaryDown atomic.Bool
+	var primaryCalls, mirrorCalls atomic.Int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorCalls.Add(1)
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	}))
+	defer mirror.Close()
+
+	ctx := context.Background()
+	h, err := NewWithOptions(ctx,
+		WithEndpoints(primary.URL, mirror.URL),
+		WithPrivateKey(testPrivateKey),
+		WithRetryPolicy(NoRetry()),
+		WithHealthCheck(10*time.Millisecond, 2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	primaryDown.Store(true)
+	for i := 0; i < 3; i++ {
+		if _, err := h.LoadTasks(ctx, &TaskQueryParams{}); err != nil {
+			t.Fatalf("LoadTasks() err = %v", err)
+		}
+	}
+	if mirrorCalls.Load() < 3 {
+		t.Errorf("mirror calls = %d, want at least 3", mirrorCalls.Load())
+	}
+	if status := h.Endpoints(); status[0].Healthy || !status[1].Healthy {
+		t.Errorf("Endpoints() = %+v, want primary unhealthy", status)
+	}
+
+	before := primaryCalls.Load()
+	if _, err := h.AddProvingTask(ctx, &ProvingParams{MD5: "md5"}); err == nil {
+		t.Errorf("AddProvingTask() err = nil, want the primary's error")
+	}
+	if primaryCalls.Load() == before {
+		t.Errorf("write did not go to the primary")
+	}
+
+	primaryDown.Store(false)
+	deadline := time.Now().Add(2 * time.Second)
+	for !h.Endpoints()[0].Healthy {
+		if time.Now().After(deadline) {
+			t.Fatal("primary not healthy again after health checks")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEndpointFailback(t *testing.T) {
+	var primaryDown atomic.Bool
+	var primaryCalls atomic.Int32
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	}))
+	defer mirror.Close()
+
+	ctx := context.Background()
+	h, err := NewWithOptions(ctx,
+		WithEndpoints(primary.URL, mirror.URL),
+		WithPrivateKey(testPrivateKey),
+		WithRetryPolicy(NoRetry()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	h.endpoints.cooldown = 50 * time.Millisecond
+
+	load := func() {
+		t.Helper()
+		if _, err := h.LoadTasks(ctx, &TaskQueryParams{}); err != nil {
+			t.Fatalf("LoadTasks() err = %v", err)
+		}
+	}
+
+	primaryDown.Store(true)
+	for i := 0; i < DefaultFailureThreshold; i++ {
+		load()
+	}
+	if h.Endpoints()[0].Healthy {
+		t.Fatalf("primary healthy after %d failures", DefaultFailureThreshold)
+	}
+
+	before := primaryCalls.Load()
+	load()
+	if primaryCalls.Load() != before {
+		t.Errorf("read sent to the unhealthy primary during the cool-down")
+	}
+
+	primaryDown.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	load()
+	if primaryCalls.Load() == before {
+		t.Errorf("read not sent to the primary after the cool-down")
+	}
+	if !h.Endpoints()[0].Healthy {
+		t.Errorf("primary not healthy after a successful read")
+	}
+}
@@ -0,0 +1,168 @@
+package zkwasm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrImageNotFound       = errors.New("image not found")
+	ErrTaskNotFound        = errors.New("task not found")
+	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	ErrImageAlreadyExists  = errors.New("image already exists")
+)
+
+// APIError is returned when the zkWasm service answers with a non-200 status
+// or with success=false. It matches the sentinel errors above with errors.Is
+// when the server message can be classified.
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	Message    string
+	// Body is the raw response body, truncated to 64KiB.
+	Body []byte
+	// RetryAfter is the delay requested by the server's Retry-After header.
+	RetryAfter time.Duration
+
+	kind error
+}
+
+func newAPIError(endpoint string, statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+		Body:       body,
+	}
+
+	respErr, ok := parseResponseError(body)
+	if !ok {
+		e.Message = strings.TrimSpace(string(body))
+		return e
+	}
+
+	e.Message = respErr.Message
+	e.kind = classifyError(endpoint, statusCode, respErr)
+
+	return e
+}
+
+// newResponseAPIError builds the error of a Response envelope with
+// success=false. respErr may be nil.
+func newResponseAPIError(endpoint string, statusCode int, respErr *ResponseError) *APIError {
+	if respErr == nil {
+		respErr = &ResponseError{}
+	}
+
+	return &APIError{
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+		Message:    respErr.Message,
+		kind:       classifyError(endpoint, statusCode, respErr),
+	}
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Body)
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	return fmt.Sprintf("zkwasm %s: status %d: %s", e.Endpoint, e.StatusCode, msg)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// IsServerError reports whether the failure is on the service side (5xx),
+// as opposed to a rejected request.
+func (e *APIError) IsServerError() bool {
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
+func (e *APIError) IsClientError() bool {
+	return e.StatusCode >= http.StatusBadRequest && e.StatusCode < http.StatusInternalServerError
+}
+
+// parseResponseError returns the error of a Response envelope, and false when
+// body is not one, i.e. when the zkWasm service itself did not answer.
+func parseResponseError(body []byte) (*ResponseError, bool) {
+	var response struct {
+		Success *bool          `json:"success"`
+		Error   *ResponseError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || (response.Success == nil && response.Error == nil) {
+		return nil, false
+	}
+
+	if response.Error == nil {
+		return &ResponseError{}, true
+	}
+
+	return response.Error, true
+}
+
+// classifyError maps the error of a service response to a sentinel, by its
+// code first and by its message otherwise. Errors which are not service
+// responses, e.g. from a gateway, are never classified.
+func classifyError(endpoint string, statusCode int, respErr *ResponseError) error {
+	if kind := classifyCode(endpoint, respErr.Code); kind != nil {
+		return kind
+	}
+
+	m := strings.ToLower(respErr.Message)
+
+	switch {
+	case strings.Contains(m, "signature"):
+		return ErrInvalidSignature
+	case strings.Contains(m, "insufficient") || strings.Contains(m, "not enough credit") ||
+		strings.Contains(m, "not enough balance"):
+		return ErrInsufficientCredits
+	case strings.Contains(m, "already exist"):
+		return ErrImageAlreadyExists
+	case strings.Contains(m, "not found") || statusCode == http.StatusNotFound:
+		return notFound(endpoint, m)
+	}
+
+	return nil
+}
+
+// classifyCode maps ResponseError.Code, either a name such as
+// "INVALID_SIGNATURE" or an HTTP-like number such as 404.
+func classifyCode(endpoint, code string) error {
+	c := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(code))
+
+	switch {
+	case c == "":
+		return nil
+	case strings.Contains(c, "signature"):
+		return ErrInvalidSignature
+	case strings.Contains(c, "insufficient"):
+		return ErrInsufficientCredits
+	case strings.Contains(c, "alreadyexist") || (c == "409" && endpoint == endpointSetup):
+		return ErrImageAlreadyExists
+	case strings.Contains(c, "notfound") || c == "404":
+		return notFound(endpoint, c)
+	}
+
+	return nil
+}
+
+func notFound(endpoint, hint string) error {
+	switch {
+	case strings.Contains(hint, "task") || endpoint == endpointTasks:
+		return ErrTaskNotFound
+	case strings.Contains(hint, "image") || endpoint == endpointImage || endpoint == endpointImageBinary:
+		return ErrImageNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package zkwasm
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoint    string
+		statusCode  int
+		body        string
+		wantMessage string
+		wantKind    error
+	}{
+		{
+			name:        "string error",
+			endpoint:    endpointProve,
+			statusCode:  http.StatusBadRequest,
+			body:        `{"success":false,"error":"Invalid signature"}`,
+			wantMessage: "Invalid signature",
+			wantKind:    ErrInvalidSignature,
+		},
+		{
+			name:        "object error",
+			endpoint:    endpointSetup,
+			statusCode:  http.StatusBadRequest,
+			body:        `{"success":false,"error":{"code":409,"message":"Image with md5 already exists"}}`,
+			wantMessage: "Image with md5 already exists",
+			wantKind:    ErrImageAlreadyExists,
+		},
+		{
+			name:        "insufficient credits",
+			endpoint:    endpointProve,
+			statusCode:  http.StatusBadRequest,
+			body:        `{"success":false,"error":{"message":"Insufficient credits for user"}}`,
+			wantMessage: "Insufficient credits for user",
+			wantKind:    ErrInsufficientCredits,
+		},
+		{
+			name:        "task not found",
+			endpoint:    endpointTasks,
+			statusCode:  http.StatusNotFound,
+			body:        `{"success":false,"error":"not found"}`,
+			wantMessage: "not found",
+			wantKind:    ErrTaskNotFound,
+		},
+		{
+			name:        "image not found",
+			endpoint:    endpointImageBinary,
+			statusCode:  http.StatusNotFound,
+			body:        `{"success":false}`,
+			wantMessage: "",
+			wantKind:    ErrImageNotFound,
+		},
+		{
+			name:        "code before message",
+			endpoint:    endpointProve,
+			statusCode:  http.StatusBadRequest,
+			body:        `{"success":false,"error":{"code":"INSUFFICIENT_CREDITS","message":"payment required"}}`,
+			wantMessage: "payment required",
+			wantKind:    ErrInsufficientCredits,
+		},
+		{
+			name:        "not found code",
+			endpoint:    endpointProve,
+			statusCode:  http.StatusBadRequest,
+			body:        `{"success":false,"error":{"code":"TASK_NOT_FOUND","message":"no such id"}}`,
+			wantMessage: "no such id",
+			wantKind:    ErrTaskNotFound,
+		},
+		{
+			name:        "gateway signature error",
+			endpoint:    endpointProve,
+			statusCode:  http.StatusForbidden,
+			body:        `The request signature we calculated does not match the signature you provided`,
+			wantMessage: `The request signature we calculated does not match the signature you provided`,
+			wantKind:    nil,
+		},
+		{
+			name:        "gateway json error",
+			endpoint:    endpointSetup,
+			statusCode:  http.StatusConflict,
+			body:        `{"message":"resource already exists"}`,
+			wantMessage: `{"message":"resource already exists"}`,
+			wantKind:    nil,
+		},
+		{
+			name:        "gateway 404",
+			endpoint:    endpointTasks,
+			statusCode:  http.StatusNotFound,
+			body:        `<html><head><title>404 Not Found</title></head><body>404 Not Found</body></html>`,
+			wantMessage: `<html><head><title>404 Not Found</title></head><body>404 Not Found</body></html>`,
+			wantKind:    nil,
+		},
+		{
+			name:        "json 404 without envelope",
+			endpoint:    endpointImage,
+			statusCode:  http.StatusNotFound,
+			body:        `{"message":"Not Found"}`,
+			wantMessage: `{"message":"Not Found"}`,
+			wantKind:    nil,
+		},
+		{
+			name:        "bad gateway",
+			endpoint:    endpointTasks,
+			statusCode:  http.StatusBadGateway,
+			body:        `<html>502 Bad Gateway</html>`,
+			wantMessage: "<html>502 Bad Gateway</html>",
+			wantKind:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error = newAPIError(tt.endpoint, tt.statusCode, []byte(tt.body))
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("errors.As(%v) = false", err)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+			if tt.wantKind != nil && !errors.Is(err, tt.wantKind) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantKind)
+			}
+			if tt.wantKind == nil && errors.Unwrap(err) != nil {
+				t.Errorf("Unwrap() = %v, want nil", errors.Unwrap(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package zkwasm
+
+import (
+	"cmp"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// FeeDecimals is the number of decimals of the service's fee unit.
+const FeeDecimals = 18
+
+// Fee decodes TaskFee, a little-endian unsigned integer in the service's
+// smallest unit. It returns 0 when the task has no fee.
+func (t *Task) Fee() *big.Int {
+	b := slices.Clone(t.TaskFee)
+	slices.Reverse(b)
+	return new(big.Int).SetBytes(b)
+}
+
+// FormatFee formats a fee in whole units with FeeDecimals decimals, e.g.
+// "0.005".
+func FormatFee(fee *big.Int) string {
+	return FormatUnits(fee, FeeDecimals)
+}
+
+// FormatUnits formats v divided by 10^decimals without trailing zeros.
+func FormatUnits(v *big.Int, decimals int) string {
+	if v == nil {
+		return "0"
+	}
+	s := new(big.Int).Abs(v).String()
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals <= 0 {
+		return sign + s
+	}
+
+	if len(s) <= decimals {
+		s = strings.Repeat("0", decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// FeeKey groups fees by user, image and task type. UserAddress is lower
+// case.
+type FeeKey struct {
+	UserAddress string
+	MD5         string
+	TaskType    string
+}
+
+type FeeSummary struct {
+	FeeKey
+	Tasks int
+	Total *big.Int
+}
+
+// SummarizeFees totals the fees of tasks per user, image md5 and task type.
+// The result is sorted by user, md5 and task type.
+func SummarizeFees(tasks []*Task) []FeeSummary {
+	groups := map[FeeKey]*FeeSummary{}
+	for _, task := range tasks {
+		key := FeeKey{
+			UserAddress: strings.ToLower(task.UserAddress),
+			MD5:         strings.ToLower(task.MD5),
+			TaskType:    task.TaskType,
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &FeeSummary{FeeKey: key, Total: new(big.Int)}
+			groups[key] = g
+		}
+		g.Tasks++
+		g.Total.Add(g.Total, task.Fee())
+	}
+
+	summaries := make([]FeeSummary, 0, len(groups))
+	for _, g := range groups {
+		summaries = append(summaries, *g)
+	}
+	slices.SortFunc(summaries, func(a, b FeeSummary) int {
+		if c := cmp.Compare(a.UserAddress, b.UserAddress); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.MD5, b.MD5); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.TaskType, b.TaskType)
+	})
+	return summaries
+}
+
+// TotalFees sums the fees of tasks.
+func TotalFees(tasks []*Task) *big.Int {
+	total := new(big.Int)
+	for _, task := range tasks {
+		total.Add(total, task.Fee())
+	}
+	return total
+}
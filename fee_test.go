@@ -0,0 +1,73 @@
+package zkwasm
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestTaskFee(t *testing.T) {
+	tests := []struct {
+		fee  []byte
+		want string
+	}{
+		{nil, "0"},
+		{[]byte{0x01}, "1"},
+		{[]byte{0x00, 0x01}, "256"},
+		// 5000000000000000 little-endian
+		{[]byte{0x00, 0x80, 0xe0, 0x37, 0x79, 0xc3, 0x11, 0x00}, "5000000000000000"},
+	}
+	for _, tt := range tests {
+		task := &Task{TaskFee: tt.fee}
+		if got := task.Fee().String(); got != tt.want {
+			t.Errorf("Fee(%x) = %s, want %s", tt.fee, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		v        string
+		decimals int
+		want     string
+	}{
+		{"0", 18, "0"},
+		{"5000000000000000", 18, "0.005"},
+		{"1000000000000000000", 18, "1"},
+		{"12345000000000000000", 18, "12.345"},
+		{"1", 18, "0.000000000000000001"},
+		{"-1500", 3, "-1.5"},
+		{"42", 0, "42"},
+	}
+	for _, tt := range tests {
+		v, _ := new(big.Int).SetString(tt.v, 10)
+		if got := FormatUnits(v, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%s, %d) = %s, want %s", tt.v, tt.decimals, got, tt.want)
+		}
+	}
+	if got := FormatFee(big.NewInt(5e15)); got != "0.005" {
+		t.Errorf("FormatFee() = %s", got)
+	}
+}
+
+func TestSummarizeFees(t *testing.T) {
+	tasks := []*Task{
+		{UserAddress: "0xB", MD5: "AA", TaskType: "Prove", TaskFee: []byte{10}},
+		{UserAddress: "0xa", MD5: "bb", TaskType: "Prove", TaskFee: []byte{1}},
+		{UserAddress: "0xb", MD5: "aa", TaskType: "Prove", TaskFee: []byte{5}},
+		{UserAddress: "0xb", MD5: "aa", TaskType: "Setup", TaskFee: []byte{0, 1}},
+		{UserAddress: "0xa", MD5: "bb", TaskType: "Prove"},
+	}
+
+	var got []string
+	for _, s := range SummarizeFees(tasks) {
+		got = append(got, fmt.Sprintf("%s/%s/%s:%d:%s", s.UserAddress, s.MD5, s.TaskType, s.Tasks, s.Total))
+	}
+	want := "[0xa/bb/Prove:2:1 0xb/aa/Prove:2:15 0xb/aa/Setup:1:256]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("SummarizeFees() = %v, want %s", got, want)
+	}
+	if total := TotalFees(tasks); total.Int64() != 272 {
+		t.Errorf("TotalFees() = %s, want 272", total)
+	}
+}
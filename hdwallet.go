@@ -0,0 +1,149 @@
+package zkwasm
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const hardenedKeyStart = 0x80000000
+
+var (
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+	ErrInvalidHDKey    = errors.New("derived key is invalid, use the next index")
+)
+
+// HDWallet derives BIP-44 accounts from a BIP-39 mnemonic.
+type HDWallet struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+// NewHDWallet builds the BIP-32 master key of mnemonic. passphrase is the
+// optional BIP-39 passphrase, not a keystore password.
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, ErrInvalidHDKey
+	}
+
+	return &HDWallet{
+		key:       key,
+		chainCode: sum[32:],
+	}, nil
+}
+
+func (w *HDWallet) Derive(path accounts.DerivationPath) (*PrivateKeySigner, error) {
+	key, chainCode := w.key, w.chainCode
+
+	for _, i := range path {
+		var err error
+		key, chainCode, err = deriveChild(key, chainCode, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	privK, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKeySigner(privK), nil
+}
+
+func (w *HDWallet) DerivePath(path string) (*PrivateKeySigner, error) {
+	p, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Derive(p)
+}
+
+// DeriveIndex derives the account at m/44'/60'/0'/0/index.
+func (w *HDWallet) DeriveIndex(index uint32) (*PrivateKeySigner, error) {
+	path := make(accounts.DerivationPath, len(accounts.DefaultBaseDerivationPath))
+	copy(path, accounts.DefaultBaseDerivationPath)
+	path[len(path)-1] = index
+
+	return w.Derive(path)
+}
+
+// Addresses returns the addresses of the first n accounts derived with
+// DeriveIndex.
+func (w *HDWallet) Addresses(n int) ([]common.Address, error) {
+	addresses := make([]common.Address, 0, n)
+
+	for i := 0; i < n; i++ {
+		s, err := w.DeriveIndex(uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, s.Address())
+	}
+
+	return addresses, nil
+}
+
+// NewMnemonicSigner derives the signer at path, e.g. "m/44'/60'/0'/0/0".
+func NewMnemonicSigner(mnemonic, passphrase, path string) (*PrivateKeySigner, error) {
+	w, err := NewHDWallet(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.DerivePath(path)
+}
+
+// deriveChild implements BIP-32 private parent key to private child key
+// derivation.
+func deriveChild(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedKeyStart {
+		data = append(data, 0)
+		data = append(data, math.PaddedBigBytes(key, 32)...)
+	} else {
+		privK, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&privK.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, nil, ErrInvalidHDKey
+	}
+
+	child := il.Add(il, key)
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, ErrInvalidHDKey
+	}
+
+	return child, sum[32:], nil
+}
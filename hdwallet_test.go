@@ -0,0 +1,44 @@
+package zkwasm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWallet(t *testing.T) {
+	w, err := NewHDWallet(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []common.Address{
+		common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+	}
+
+	got, err := w.Addresses(len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Addresses()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	s, err := NewMnemonicSigner(testMnemonic, "", "m/44'/60'/0'/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != want[1] {
+		t.Errorf("NewMnemonicSigner() address = %s, want %s", s.Address(), want[1])
+	}
+
+	if _, err := NewHDWallet("test test test", ""); err != ErrInvalidMnemonic {
+		t.Errorf("invalid mnemonic err = %v, want %v", err, ErrInvalidMnemonic)
+	}
+}
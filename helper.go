@@ -2,14 +2,18 @@ package zkwasm
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"strings"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
 
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -18,60 +22,201 @@ const (
 	endpointTasks       = "/tasks"
 	endpointProve       = "/prove"
 	endpointSetup       = "/setup"
+	endpointConfig      = "/config"
 
 	headerSignatureKey = "x-eth-signature"
 )
 
 type ZkWasmServiceHelper struct {
-	zkWasmEndpoint string
-	ethEndpoint    string
+	endpoints   *endpointPool
+	ethEndpoint string
 
-	wallet      *ecdsa.PrivateKey
+	signer      Signer
 	userAddress common.Address
 
 	ethClient *ethclient.Client
 
 	verifyContractAddress common.Address
+
+	httpClient *http.Client
+	userAgent  string
+	headers    http.Header
+
+	retryPolicy RetryPolicy
+	middlewares []Middleware
+
+	logger       *slog.Logger
+	logSensitive bool
+
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	metrics Metrics
+
+	maxResponseSize int64
+	bulkConcurrency int
+
+	stopHealthCheck context.CancelFunc
+	healthCheckDone chan struct{}
+
+	configMu sync.Mutex
+	config   *ServiceConfig
 }
 
+var (
+	ErrNoBaseURL   = errors.New("zkWasm endpoint is not set")
+	ErrNoSigner    = errors.New("no private key configured, helper is read-only")
+	ErrNoEthClient = errors.New("no eth endpoint configured")
+)
+
 func New(zkWasmEndpoint, ethEndpoint, privateKey, contractAddress string) (*ZkWasmServiceHelper, error) {
 	return NewWithContext(context.Background(), zkWasmEndpoint, ethEndpoint, privateKey, contractAddress)
 }
 
 func NewWithContext(ctx context.Context, zkWasmEndpoint, ethEndpoint, privateKey, contractAddress string) (*ZkWasmServiceHelper, error) {
+	return NewWithOptions(ctx,
+		WithBaseURL(zkWasmEndpoint),
+		WithEthEndpoint(ethEndpoint),
+		WithPrivateKey(privateKey),
+		WithContractAddress(contractAddress),
+	)
+}
+
+// NewFromKeystore is like NewWithContext, but reads the private key from an
+// encrypted V3 keystore file.
+func NewFromKeystore(ctx context.Context, zkWasmEndpoint, ethEndpoint, keystorePath, passphrase, contractAddress string) (*ZkWasmServiceHelper, error) {
+	return NewWithOptions(ctx,
+		WithBaseURL(zkWasmEndpoint),
+		WithEthEndpoint(ethEndpoint),
+		WithKeystoreFile(keystorePath, passphrase),
+		WithContractAddress(contractAddress),
+	)
+}
+
+// NewReadOnly builds a helper which only talks to the zkWasm service. It needs
+// neither a private key nor an eth endpoint; signing methods return
+// ErrNoSigner and ContractVerify returns ErrNoEthClient.
+func NewReadOnly(ctx context.Context, zkWasmEndpoint string, opts ...Option) (*ZkWasmServiceHelper, error) {
+	return NewWithOptions(ctx, append([]Option{WithBaseURL(zkWasmEndpoint)}, opts...)...)
+}
+
+// NewWithOptions builds a helper from opts. WithBaseURL is required, the eth
+// endpoint and private key are optional, see NewReadOnly.
+func NewWithOptions(ctx context.Context, opts ...Option) (*ZkWasmServiceHelper, error) {
+	o := &options{}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(o.endpoints) == 0 || slices.Contains(o.endpoints, "") {
+		return nil, ErrNoBaseURL
+	}
+
 	h := &ZkWasmServiceHelper{}
 
-	h.zkWasmEndpoint = strings.TrimSuffix(zkWasmEndpoint, "/")
-	h.ethEndpoint = strings.TrimSuffix(ethEndpoint, "/")
+	h.endpoints = newEndpointPool(o.endpoints, o.failureThreshold)
+	h.ethEndpoint = o.ethEndpoint
 
-	ethC, err := ethclient.DialContext(ctx, h.ethEndpoint)
-	if err != nil {
-		return nil, err
+	h.httpClient = o.buildHTTPClient()
+	h.userAgent = o.userAgent
+	h.headers = o.headers
+
+	h.middlewares = o.middlewares
+
+	h.logger = o.logger
+	if h.logger == nil {
+		h.logger = slog.New(discardHandler{})
 	}
-	h.ethClient = ethC
+	h.logSensitive = o.logSensitive
 
-	h.verifyContractAddress = common.HexToAddress(contractAddress)
+	tp := o.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	h.tracer = tp.Tracer(tracerName)
+	h.propagator = o.propagator
+	if h.propagator == nil {
+		h.propagator = otel.GetTextMapPropagator()
+	}
 
-	privK, err := crypto.HexToECDSA(privateKey)
-	if err != nil {
-		return nil, err
+	h.retryPolicy = DefaultRetryPolicy()
+	if o.retryPolicy != nil {
+		h.retryPolicy = *o.retryPolicy
+	}
+
+	if h.ethEndpoint != "" {
+		ethC, err := ethclient.DialContext(ctx, h.ethEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		h.ethClient = ethC
+	}
+
+	h.verifyContractAddress = common.HexToAddress(o.contractAddress)
+
+	if o.signer != nil {
+		h.signer = o.signer
+		h.userAddress = o.signer.Address()
+	}
+
+	h.maxResponseSize = o.maxResponseSize
+	if h.maxResponseSize == 0 {
+		h.maxResponseSize = DefaultMaxResponseSize
 	}
 
-	h.wallet = privK
-	h.userAddress = crypto.PubkeyToAddress(*h.wallet.Public().(*ecdsa.PublicKey))
+	h.bulkConcurrency = o.bulkConcurrency
+	if h.bulkConcurrency <= 0 {
+		h.bulkConcurrency = DefaultBulkConcurrency
+	}
+
+	h.metrics = o.metrics
+	if h.metrics == nil {
+		h.metrics = noopMetrics{}
+	}
+
+	if o.discoverConfig {
+		if _, err := h.QueryConfig(ctx); err != nil {
+			if h.ethClient != nil {
+				h.ethClient.Close()
+			}
+			return nil, err
+		}
+	}
+
+	if o.healthCheckInterval > 0 {
+		h.startHealthCheck(o.healthCheckInterval)
+	}
 
 	return h, nil
 }
 
+// GetUserAddress returns an empty string for a read-only helper.
 func (h *ZkWasmServiceHelper) GetUserAddress() string {
+	if h.signer == nil {
+		return ""
+	}
+
 	return h.userAddress.Hex()
 }
 
-func (h *ZkWasmServiceHelper) signMessage(message string, legacyV bool) (string, error) {
-	hash := accounts.TextHash([]byte(message))
+func (h *ZkWasmServiceHelper) IsReadOnly() bool {
+	return h.signer == nil
+}
+
+func (h *ZkWasmServiceHelper) signMessage(ctx context.Context, message string, legacyV bool) (string, error) {
+	if h.signer == nil {
+		return "", ErrNoSigner
+	}
 
-	sign, err := crypto.Sign(hash, h.wallet)
+	sign, err := h.signer.SignText(ctx, []byte(message))
 	if err != nil {
+		h.logger.ErrorContext(ctx, "sign message failed",
+			slog.String("address", h.userAddress.Hex()),
+			slog.String("message", h.redact(message)),
+			slog.Any("error", err),
+		)
 		return "", err
 	}
 
@@ -80,5 +225,13 @@ func (h *ZkWasmServiceHelper) signMessage(message string, legacyV bool) (string,
 		sign[64] += 27
 	}
 
-	return hexutil.Encode(sign), nil
+	signature := hexutil.Encode(sign)
+
+	h.logger.DebugContext(ctx, "signed message",
+		slog.String("address", h.userAddress.Hex()),
+		slog.String("message", h.redact(message)),
+		slog.String("signature", h.redact(signature)),
+	)
+
+	return signature, nil
 }
@@ -5,16 +5,18 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -23,10 +25,6 @@ const (
 	ImageMetadataValsProvePaymentSrcDefault    = "Default"
 	ImageMetadataValsProvePaymentSrcCreatorPay = "CreatorPay"
 
-	ImageStatusReceived    = "Received"
-	ImageStatusInitialized = "Initialized"
-	ImageStatusVerified    = "Verified"
-
 	ImageCircuitSizeDefault = 22
 )
 
@@ -34,44 +32,68 @@ type Image struct {
 	UserAddress string `json:"user_address"`
 	MD5         string `json:"md5"`
 	// deployment: Array<DeploymentInfo>;
-	DescriptionUrl string `json:"description_url"`
-	AvatorUrl      string `json:"avator_url"`
-	CircuitSize    int64  `json:"circuit_size"`
-	Context        []byte `json:"context"`
-	InitialContext []byte `json:"initial_context"`
-	Status         string `json:"status"`
+	DescriptionUrl string      `json:"description_url"`
+	AvatorUrl      string      `json:"avator_url"`
+	CircuitSize    int64       `json:"circuit_size"`
+	Context        []byte      `json:"context"`
+	InitialContext []byte      `json:"initial_context"`
+	Status         ImageStatus `json:"status"`
 	// checksum: ImageChecksum | null;
 }
 
+type ImageQueryParams struct {
+	UserAddress string `json:"user_address,omitempty"`
+	MD5         string `json:"md5,omitempty"`
+	Start       int64  `json:"start,omitempty"`
+	Total       int64  `json:"total,omitempty"`
+}
+
 type AddImageResult struct {
 	ID  string `json:"id"`
 	MD5 string `json:"md5"`
 }
 
 type AddImageParams struct {
-	Name           string `json:"name"`
-	Image          []byte `json:"image"`
-	ImageMD5       string `json:"image_md5"`
-	UserAddress    string `json:"user_address"`
-	DescriptionUrl string `json:"description_url"`
-	AvatorUrl      string `json:"avator_url"`
-	CircuitSize    int64  `json:"circuit_size"`
+	Name  string `json:"name"`
+	Image []byte `json:"image"`
+	// ImageReader streams the image instead of Image, setting both is an
+	// error. ImageMD5 must be set unless ImageReader is an io.Seeker, in which
+	// case it is computed.
+	ImageReader    io.Reader `json:"-"`
+	ImageMD5       string    `json:"image_md5"`
+	UserAddress    string    `json:"user_address"`
+	DescriptionUrl string    `json:"description_url"`
+	AvatorUrl      string    `json:"avator_url"`
+	CircuitSize    int64     `json:"circuit_size"`
 
 	MetadataKeys []string `json:"metadata_keys"`
 	MetadataVals []string `json:"metadata_vals"`
 
 	InitialContext    []byte `json:"initial_context,omitempty"`
 	InitialContextMD5 string `json:"initial_context_md5,omitempty"`
+
+	// Progress reports the upload of the image.
+	Progress ProgressFunc `json:"-"`
 }
 
-func (p *AddImageParams) fillValues(userAddress string) {
+func (p *AddImageParams) fillValues(userAddress string) error {
 	// overwrite user address
 	p.UserAddress = strings.ToLower(userAddress)
 
+	if p.Image != nil && p.ImageReader != nil {
+		return ErrImageSourceConflict
+	}
 	if p.Image != nil {
 		s := md5.Sum(p.Image)
-		p.ImageMD5 = strings.ToLower(hex.EncodeToString(s[:]))
+		p.ImageMD5 = hex.EncodeToString(s[:])
+	} else if p.ImageReader != nil && p.ImageMD5 == "" {
+		sum, err := readerMD5(p.ImageReader)
+		if err != nil {
+			return err
+		}
+		p.ImageMD5 = sum
 	}
+	p.ImageMD5 = strings.ToLower(p.ImageMD5)
 
 	if p.CircuitSize == 0 {
 		p.CircuitSize = ImageCircuitSizeDefault
@@ -86,6 +108,40 @@ func (p *AddImageParams) fillValues(userAddress string) {
 		s := md5.Sum(p.InitialContext)
 		p.InitialContextMD5 = strings.ToLower(hex.EncodeToString(s[:]))
 	}
+
+	return nil
+}
+
+// readerMD5 hashes r and rewinds it, r must be an io.Seeker.
+func readerMD5(r io.Reader) (string, error) {
+	rewind, ok := rewinder(r)
+	if !ok {
+		return "", ErrImageMD5Required
+	}
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+
+	if err := rewind(); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (p *AddImageParams) imageSource() (io.Reader, func() error, bool) {
+	if p.ImageReader == nil {
+		r := bytes.NewReader(p.Image)
+		return r, func() error {
+			_, err := r.Seek(0, io.SeekStart)
+			return err
+		}, true
+	}
+
+	rewind, ok := rewinder(p.ImageReader)
+	return p.ImageReader, rewind, ok
 }
 
 func (p *AddImageParams) buildSignMessage() string {
@@ -113,129 +169,187 @@ func (p *AddImageParams) buildSignMessage() string {
 }
 
 func (h *ZkWasmServiceHelper) QueryImage(ctx context.Context, md5 string) (*Image, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.zkWasmEndpoint+endpointImage, nil)
-	if err != nil {
-		return nil, err
-	}
+	ctx, span := h.startSpan(ctx, "zkwasm.QueryImage", attrImageMD5.String(md5))
+	defer span.End()
 
-	q := req.URL.Query()
+	q := url.Values{}
 	q.Add("md5", md5)
-	req.URL.RawQuery = q.Encode()
 
-	resp, err := http.DefaultClient.Do(req)
+	images, err := doRequest[[]*Image](ctx, h, &apiCall{
+		method:   http.MethodGet,
+		endpoint: endpointImage,
+		query:    q,
+	})
 	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "query image failed", slog.String("md5", md5), slog.Any("error", err))
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+	h.logger.DebugContext(ctx, "queried image", slog.String("md5", md5), slog.Int("count", len(images)))
 
-	response := &Response[[]*Image]{}
-	if err := json.Unmarshal(body, response); err != nil {
-		return nil, err
+	if len(images) == 0 {
+		return nil, nil
 	}
 
-	if !response.Success {
-		return nil, errors.New(string(body))
+	return images[0], nil
+}
+
+// LoadImages returns one page of images. The service does not report a
+// total, so a page shorter than query.Total is the last one.
+func (h *ZkWasmServiceHelper) LoadImages(ctx context.Context, query *ImageQueryParams) ([]*Image, error) {
+	ctx, span := h.startSpan(ctx, "zkwasm.LoadImages", attrImageMD5.String(query.MD5))
+	defer span.End()
+
+	q := url.Values{}
+	if query.UserAddress != "" {
+		q.Add("user_address", query.UserAddress)
+	}
+	if query.MD5 != "" {
+		q.Add("md5", query.MD5)
+	}
+	if query.Start != 0 {
+		q.Add("start", strconv.FormatInt(query.Start, 10))
+	}
+	if query.Total != 0 {
+		q.Add("total", strconv.FormatInt(query.Total, 10))
 	}
 
-	if len(response.Result) == 0 {
-		return nil, nil
+	images, err := doRequest[[]*Image](ctx, h, &apiCall{
+		method:   http.MethodGet,
+		endpoint: endpointImage,
+		query:    q,
+	})
+	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "load images failed",
+			slog.String("user_address", query.UserAddress),
+			slog.Any("error", err),
+		)
+		return nil, err
 	}
 
-	return response.Result[0], nil
+	span.SetAttributes(attribute.Int("zkwasm.images.count", len(images)))
+	h.logger.DebugContext(ctx, "loaded images",
+		slog.String("user_address", query.UserAddress),
+		slog.Int("count", len(images)),
+	)
+
+	return images, nil
 }
 
 func (h *ZkWasmServiceHelper) AddNewWasmImage(ctx context.Context, params *AddImageParams) (string, error) {
-	params.fillValues(h.GetUserAddress())
+	ctx, span := h.startSpan(ctx, "zkwasm.AddNewWasmImage")
+	defer span.End()
 
-	signMsg := params.buildSignMessage()
-	sign, err := h.signMessage(signMsg, true)
-	if err != nil {
+	if err := params.fillValues(h.GetUserAddress()); err != nil {
+		recordError(span, err)
 		return "", err
 	}
+	span.SetAttributes(attrImageMD5.String(params.ImageMD5))
 
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
-
-	w.WriteField("name", params.Name)
-
-	imageHeader := make(textproto.MIMEHeader)
-	imageHeader.Set("Content-Type", "application/wasm")
-	imageHeader.Set("Content-Disposition",
-		fmt.Sprintf(`form-data; name="image"; filename="%s"`, params.Name))
-	iw, err := w.CreatePart(imageHeader)
-	if err != nil {
+	if err := h.validateCircuitSize(params.CircuitSize); err != nil {
+		recordError(span, err)
 		return "", err
 	}
-	if _, err := iw.Write(params.Image); err != nil {
+
+	signMsg := params.buildSignMessage()
+	sign, err := h.signMessage(ctx, signMsg, true)
+	if err != nil {
+		recordError(span, err)
 		return "", err
 	}
-	w.WriteField("image_md5", params.ImageMD5)
 
-	w.WriteField("user_address", params.UserAddress)
-	w.WriteField("description_url", params.DescriptionUrl)
-	w.WriteField("avator_url", params.AvatorUrl)
-	w.WriteField("circuit_size", strconv.FormatInt(params.CircuitSize, 10))
-	for _, k := range params.MetadataKeys {
-		w.WriteField("metadata_keys", k)
-	}
-	for _, v := range params.MetadataVals {
-		w.WriteField("metadata_vals", v)
-	}
+	image, rewind, replayable := params.imageSource()
+	imageSize := readerSize(image)
+	var uploaded int64
 
-	if len(params.InitialContext) != 0 {
-		icw, err := w.CreateFormField("initial_context")
-		if err != nil {
-			return "", err
+	stream := newMultipartStream(func(w *multipart.Writer) error {
+		if err := rewind(); err != nil {
+			return err
 		}
 
-		if _, err := icw.Write(params.InitialContext); err != nil {
-			return "", err
-		}
+		w.WriteField("name", params.Name)
 
-		w.WriteField("initial_context_md5", params.InitialContextMD5)
-	}
+		imageHeader := make(textproto.MIMEHeader)
+		imageHeader.Set("Content-Type", "application/wasm")
+		imageHeader.Set("Content-Disposition",
+			fmt.Sprintf(`form-data; name="image"; filename="%s"`, params.Name))
+		iw, err := w.CreatePart(imageHeader)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(iw, newProgressReader(image, imageSize, params.Progress))
+		if err != nil {
+			return err
+		}
+		w.WriteField("image_md5", params.ImageMD5)
+
+		w.WriteField("user_address", params.UserAddress)
+		w.WriteField("description_url", params.DescriptionUrl)
+		w.WriteField("avator_url", params.AvatorUrl)
+		w.WriteField("circuit_size", strconv.FormatInt(params.CircuitSize, 10))
+		for _, k := range params.MetadataKeys {
+			w.WriteField("metadata_keys", k)
+		}
+		for _, v := range params.MetadataVals {
+			w.WriteField("metadata_vals", v)
+		}
 
-	err = w.Close()
-	if err != nil {
-		return "", err
-	}
+		if len(params.InitialContext) != 0 {
+			icw, err := w.CreateFormField("initial_context")
+			if err != nil {
+				return err
+			}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.zkWasmEndpoint+endpointSetup, &b)
-	if err != nil {
-		return "", err
-	}
+			if _, err := icw.Write(params.InitialContext); err != nil {
+				return err
+			}
+			n += int64(len(params.InitialContext))
 
-	req.Header[headerSignatureKey] = []string{sign}
-	req.Header.Add("Content-Type", w.FormDataContentType())
+			w.WriteField("initial_context_md5", params.InitialContextMD5)
+		}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
+		uploaded = n
+		return nil
+	})
+
+	header := make(http.Header)
+	header[headerSignatureKey] = []string{sign}
+	header.Set("Content-Type", stream.contentType)
+
+	result, err := doRequest[*AddImageResult](ctx, h, &apiCall{
+		method:   http.MethodPost,
+		endpoint: endpointSetup,
+		header:   header,
+		body:     stream.body,
+		write:    true,
+		oneShot:  !replayable,
+
+		signMessage: signMsg,
+		signature:   sign,
+	})
+	if err == nil && result == nil {
+		err = errEmptyResult(endpointSetup)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "add wasm image failed",
+			slog.String("md5", params.ImageMD5),
+			slog.String("name", params.Name),
+			slog.Any("error", err),
+		)
 		return "", err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(string(body))
-	}
-
-	result := &Response[*AddImageResult]{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
-	}
-
-	if !result.Success || result.Result == nil {
-		return "", errors.New(string(body))
-	}
+	span.SetAttributes(attribute.String("zkwasm.image.id", result.ID))
+	h.metrics.AddImageUploadBytes(uploaded)
+	h.logger.InfoContext(ctx, "added wasm image",
+		slog.String("md5", params.ImageMD5),
+		slog.String("name", params.Name),
+		slog.String("id", result.ID),
+		slog.Int64("bytes", uploaded),
+	)
 
-	return result.Result.ID, nil
+	return result.ID, nil
 }
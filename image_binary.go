@@ -2,45 +2,38 @@ package zkwasm
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
-	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 )
 
 func (h *ZkWasmServiceHelper) QueryImageBinary(ctx context.Context, md5 string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.zkWasmEndpoint+endpointImageBinary, nil)
-	if err != nil {
-		return nil, err
-	}
+	return h.QueryImageBinaryWithProgress(ctx, md5, nil)
+}
 
-	q := req.URL.Query()
+// QueryImageBinaryWithProgress is QueryImageBinary reporting the download of
+// the response to progress.
+func (h *ZkWasmServiceHelper) QueryImageBinaryWithProgress(ctx context.Context, md5 string, progress ProgressFunc) ([]byte, error) {
+	ctx, span := h.startSpan(ctx, "zkwasm.QueryImageBinary", attrImageMD5.String(md5))
+	defer span.End()
+
+	q := url.Values{}
 	q.Add("md5", md5)
-	req.URL.RawQuery = q.Encode()
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	image, err := doRequest[[]byte](ctx, h, &apiCall{
+		method:   http.MethodGet,
+		endpoint: endpointImageBinary,
+		query:    q,
 
-	body, err := io.ReadAll(resp.Body)
+		downloadProgress: progress,
+	})
 	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "query image binary failed", slog.String("md5", md5), slog.Any("error", err))
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(body))
-	}
-
-	response := &Response[[]byte]{}
-	if err := json.Unmarshal(body, response); err != nil {
-		return nil, errors.New(err.Error() + ": " + string(body))
-	}
-
-	if !response.Success {
-		return nil, errors.New(string(body))
-	}
+	h.logger.DebugContext(ctx, "queried image binary", slog.String("md5", md5), slog.Int("bytes", len(image)))
 
-	return response.Result, nil
+	return image, nil
 }
@@ -0,0 +1,171 @@
+package zkwasm
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultPageSize is used by the iterators when the query sets no Total.
+const DefaultPageSize = 100
+
+// fetchPage loads limit items starting at start. total is the number of
+// items the service reports, or -1 when it does not report one.
+type fetchPage[T any] func(ctx context.Context, start, limit int64) (items []T, total int64, err error)
+
+type page[T any] struct {
+	items []T
+	total int64
+	err   error
+}
+
+// pageIterator walks a paginated query, fetching the next page in the
+// background while the current one is consumed. Fetches run on a context
+// owned by the iterator, which keeps the values of the first Next context
+// but is only canceled by Close, so each Next may use its own context.
+type pageIterator[T any] struct {
+	fetch fetchPage[T]
+	start int64
+	limit int64
+
+	items   []T
+	current T
+	done    bool
+	err     error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	next   chan page[T]
+	once   sync.Once
+}
+
+func newPageIterator[T any](fetch fetchPage[T], start, limit int64) *pageIterator[T] {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	return &pageIterator[T]{fetch: fetch, start: start, limit: limit}
+}
+
+func (it *pageIterator[T]) prefetch(ctx context.Context) {
+	if it.ctx == nil {
+		it.ctx, it.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+	next := make(chan page[T], 1)
+	it.next = next
+
+	fetchCtx, start, limit := it.ctx, it.start, it.limit
+	go func() {
+		items, total, err := it.fetch(fetchCtx, start, limit)
+		next <- page[T]{items: items, total: total, err: err}
+	}()
+}
+
+func (it *pageIterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		return it.stop(err)
+	}
+	for len(it.items) == 0 {
+		if it.done {
+			return false
+		}
+		if it.next == nil {
+			it.prefetch(ctx)
+		}
+
+		var p page[T]
+		select {
+		case p = <-it.next:
+		case <-ctx.Done():
+			return it.stop(ctx.Err())
+		}
+		it.next = nil
+		if p.err != nil {
+			return it.stop(p.err)
+		}
+
+		it.start += int64(len(p.items))
+		it.items = p.items
+		if int64(len(p.items)) < it.limit || (p.total >= 0 && it.start >= p.total) {
+			it.done = true
+			it.cancel()
+		} else {
+			it.prefetch(ctx)
+		}
+	}
+
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+func (it *pageIterator[T]) stop(err error) bool {
+	it.err = err
+	it.Close()
+	return false
+}
+
+func (it *pageIterator[T]) Err() error {
+	return it.err
+}
+
+// Close stops any page fetch in flight. It is only needed when the
+// iteration is abandoned before Next returns false.
+func (it *pageIterator[T]) Close() {
+	it.once.Do(func() {
+		it.done, it.items = true, nil
+		if it.cancel != nil {
+			it.cancel()
+		}
+	})
+}
+
+// TaskIterator walks every page of LoadTasks.
+//
+//	it := h.IterateTasks(&TaskQueryParams{MD5: md5})
+//	for it.Next(ctx) {
+//		task := it.Task()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type TaskIterator struct {
+	*pageIterator[*Task]
+}
+
+// IterateTasks returns an iterator over all tasks matching query, starting
+// at query.Start and fetching query.Total tasks per page.
+func (h *ZkWasmServiceHelper) IterateTasks(query *TaskQueryParams) *TaskIterator {
+	q := *query
+	return &TaskIterator{newPageIterator(func(ctx context.Context, start, limit int64) ([]*Task, int64, error) {
+		q.Start, q.Total = start, limit
+		result, err := h.LoadTasks(ctx, &q)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result.Data, result.Total, nil
+	}, q.Start, q.Total)}
+}
+
+func (it *TaskIterator) Task() *Task {
+	return it.current
+}
+
+// ImageIterator walks every page of LoadImages.
+type ImageIterator struct {
+	*pageIterator[*Image]
+}
+
+// IterateImages returns an iterator over all images matching query,
+// starting at query.Start and fetching query.Total images per page.
+func (h *ZkWasmServiceHelper) IterateImages(query *ImageQueryParams) *ImageIterator {
+	q := *query
+	return &ImageIterator{newPageIterator(func(ctx context.Context, start, limit int64) ([]*Image, int64, error) {
+		q.Start, q.Total = start, limit
+		images, err := h.LoadImages(ctx, &q)
+		return images, -1, err
+	}, q.Start, q.Total)}
+}
+
+func (it *ImageIterator) Image() *Image {
+	return it.current
+}
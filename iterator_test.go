@@ -0,0 +1,153 @@
+package zkwasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func serveTasks(n int64, requested chan<- int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		total, _ := strconv.ParseInt(r.URL.Query().Get("total"), 10, 64)
+		if requested != nil {
+			requested <- start
+		}
+		tasks := []*Task{}
+		for i := start; i < min(start+total, n); i++ {
+			tasks = append(tasks, &Task{ID: strconv.FormatInt(i, 10)})
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": true,
+			"result":  map[string]any{"data": tasks, "total": n},
+		})
+	}
+}
+
+func TestTaskIterator(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int64
+		query    TaskQueryParams
+		want     int64
+		requests int
+	}{
+		{"empty", 0, TaskQueryParams{}, 0, 1},
+		{"single page", 30, TaskQueryParams{}, 30, 1},
+		{"exact pages", 20, TaskQueryParams{Total: 10}, 20, 2},
+		{"partial last page", 25, TaskQueryParams{Total: 10}, 25, 3},
+		{"offset", 25, TaskQueryParams{Start: 5, Total: 10}, 20, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := make(chan int64, 10)
+			h := newTestHelper(t, serveTasks(tt.n, requested))
+
+			it := h.IterateTasks(&tt.query)
+			next := tt.query.Start
+			for it.Next(context.Background()) {
+				if it.Task().ID != strconv.FormatInt(next, 10) {
+					t.Fatalf("Task().ID = %s, want %d", it.Task().ID, next)
+				}
+				next++
+			}
+			if err := it.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+			if got := next - tt.query.Start; got != tt.want {
+				t.Errorf("iterated %d tasks, want %d", got, tt.want)
+			}
+			if len(requested) != tt.requests {
+				t.Errorf("%d page requests, want %d", len(requested), tt.requests)
+			}
+		})
+	}
+}
+
+func TestTaskIteratorPrefetch(t *testing.T) {
+	requested := make(chan int64, 10)
+	h := newTestHelper(t, serveTasks(25, requested))
+
+	it := h.IterateTasks(&TaskQueryParams{Total: 10})
+	defer it.Close()
+	if !it.Next(context.Background()) {
+		t.Fatalf("Next() = false, err = %v", it.Err())
+	}
+	for _, want := range []int64{0, 10} {
+		select {
+		case start := <-requested:
+			if start != want {
+				t.Errorf("requested start %d, want %d", start, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("page at %d was not prefetched", want)
+		}
+	}
+}
+
+func TestTaskIteratorContextPerNext(t *testing.T) {
+	h := newTestHelper(t, serveTasks(250, nil))
+
+	it := h.IterateTasks(&TaskQueryParams{Total: 100})
+	var n int
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ok := it.Next(ctx)
+		cancel()
+		if !ok {
+			break
+		}
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if n != 250 {
+		t.Errorf("iterated %d tasks, want 250", n)
+	}
+}
+
+func TestTaskIteratorCancel(t *testing.T) {
+	h := newTestHelper(t, serveTasks(25, nil), WithRetryPolicy(NoRetry()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := h.IterateTasks(&TaskQueryParams{Total: 10})
+	var n int
+	for it.Next(ctx) {
+		if n++; n == 5 {
+			cancel()
+		}
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want %v", it.Err(), context.Canceled)
+	}
+	if n != 5 {
+		t.Errorf("iterated %d tasks, want 5", n)
+	}
+}
+
+func TestImageIterator(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		images := []*Image{}
+		for i := start; i < min(start+2, 5); i++ {
+			images = append(images, &Image{MD5: fmt.Sprint(i)})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"success": true, "result": images})
+	})
+
+	it := h.IterateImages(&ImageQueryParams{UserAddress: "0x582867abf63EbfA5803327a960AC381C2E01d67b", Total: 2})
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Image().MD5)
+	}
+	if it.Err() != nil || fmt.Sprint(got) != "[0 1 2 3 4]" {
+		t.Errorf("images = %v, err = %v", got, it.Err())
+	}
+}
@@ -0,0 +1,86 @@
+package zkwasm
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrKeystoreNotFound = errors.New("no keystore file found for address")
+)
+
+// NewKeystoreSigner decrypts a V3 keystore JSON document.
+func NewKeystoreSigner(keyJSON []byte, passphrase string) (*PrivateKeySigner, error) {
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKeySigner(key.PrivateKey), nil
+}
+
+func NewKeystoreSignerFromFile(path, passphrase string) (*PrivateKeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeystoreSigner(keyJSON, passphrase)
+}
+
+// NewKeystoreSignerFromDir looks up the keystore file of address in dir, the
+// layout used by geth and clef, and decrypts it.
+func NewKeystoreSignerFromDir(dir string, address common.Address, passphrase string) (*PrivateKeySigner, error) {
+	path, err := findKeystoreFile(dir, address)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := NewKeystoreSignerFromFile(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Address() != address {
+		return nil, ErrKeystoreNotFound
+	}
+
+	return s, nil
+}
+
+func findKeystoreFile(dir string, address common.Address) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var key struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(content, &key); err != nil {
+			continue
+		}
+
+		if common.IsHexAddress(key.Address) && common.HexToAddress(key.Address) == address {
+			return path, nil
+		}
+	}
+
+	return "", ErrKeystoreNotFound
+}
@@ -0,0 +1,48 @@
+package zkwasm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestKeystoreSigner(t *testing.T) {
+	dir := t.TempDir()
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+
+	key, err := crypto.HexToECDSA(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := ks.ImportECDSA(key, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewKeystoreSignerFromFile(account.URL.Path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != account.Address {
+		t.Errorf("Address() = %s, want %s", s.Address(), account.Address)
+	}
+
+	if _, err := NewKeystoreSignerFromFile(account.URL.Path, "wrong"); err != keystore.ErrDecrypt {
+		t.Errorf("wrong passphrase err = %v, want %v", err, keystore.ErrDecrypt)
+	}
+
+	s, err = NewKeystoreSignerFromDir(dir, account.Address, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != account.Address {
+		t.Errorf("Address() = %s, want %s", s.Address(), account.Address)
+	}
+
+	other := common.HexToAddress("0x9D48Dce80682108864F1FB719229DCd0C45E51D7")
+	if _, err := NewKeystoreSignerFromDir(dir, other, "passphrase"); err != ErrKeystoreNotFound {
+		t.Errorf("unknown address err = %v, want %v", err, ErrKeystoreNotFound)
+	}
+}
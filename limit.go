@@ -0,0 +1,82 @@
+package zkwasm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseSize bounds the size of a response body from the zkWasm
+// service, see WithMaxResponseSize.
+const DefaultMaxResponseSize = 256 << 20
+
+// maxErrorBodySize bounds the body kept in an APIError.
+const maxErrorBodySize = 64 << 10
+
+var (
+	ErrResponseTooLarge = errors.New("response too large")
+)
+
+// ResponseTooLargeError is returned when a response body exceeds the
+// configured maximum size. It matches ErrResponseTooLarge with errors.Is.
+type ResponseTooLargeError struct {
+	Endpoint string
+	Limit    int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("zkwasm %s: response exceeds %d bytes", e.Endpoint, e.Limit)
+}
+
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// maxBytesReader returns err once more than n bytes have been read from r.
+type maxBytesReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func newMaxBytesReader(r io.Reader, n int64, err error) io.Reader {
+	if n <= 0 {
+		return r
+	}
+
+	return &maxBytesReader{r: r, n: n, err: err}
+}
+
+func (l *maxBytesReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, l.err
+	}
+
+	// read one byte more than allowed to detect bodies over the limit
+	if int64(len(p))-1 > l.n {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	if int64(n) <= l.n {
+		l.n -= int64(n)
+		return n, err
+	}
+
+	n = int(l.n)
+	l.n = -1
+	return n, l.err
+}
+
+// headBuffer keeps the first n bytes written to it and discards the rest.
+type headBuffer struct {
+	buf []byte
+	n   int
+}
+
+func (b *headBuffer) Write(p []byte) (int, error) {
+	if room := b.n - len(b.buf); room > 0 {
+		b.buf = append(b.buf, p[:min(room, len(p))]...)
+	}
+	return len(p), nil
+}
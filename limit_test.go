@@ -0,0 +1,81 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	data := `{"success":true,"result":{"data":[{"id":"` + strings.Repeat("a", 4096) + `"}],"total":1}}`
+
+	tests := []struct {
+		name    string
+		limit   int64
+		chunked bool
+		wantErr bool
+	}{
+		{name: "under limit", limit: 8192, wantErr: false},
+		{name: "content length over limit", limit: 1024, wantErr: true},
+		{name: "chunked over limit", limit: 1024, chunked: true, wantErr: true},
+		{name: "disabled", limit: -1, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.chunked {
+					w.Write([]byte(data[:10]))
+					w.(http.Flusher).Flush()
+					w.Write([]byte(data[10:]))
+					return
+				}
+				w.Write([]byte(data))
+			}, WithMaxResponseSize(tt.limit))
+
+			_, err := h.LoadTasks(context.Background(), &TaskQueryParams{})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var tooLarge *ResponseTooLargeError
+			if !errors.Is(err, ErrResponseTooLarge) || !errors.As(err, &tooLarge) {
+				t.Fatalf("err = %v, want %v", err, ErrResponseTooLarge)
+			}
+			if tooLarge.Limit != tt.limit || tooLarge.Endpoint != endpointTasks {
+				t.Errorf("err = %+v", tooLarge)
+			}
+		})
+	}
+}
+
+func TestUnsuccessfulResponseBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"small", `{"success":false,"error":"Invalid signature"}`, `{"success":false,"error":"Invalid signature"}`},
+		{"truncated", `{"success":false,"error":"` + strings.Repeat("x", 2*maxErrorBodySize) + `"}`, `{"success":false,"error":"` + strings.Repeat("x", maxErrorBodySize-len(`{"success":false,"error":"`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := h.LoadTasks(context.Background(), &TaskQueryParams{})
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want an *APIError", err)
+			}
+			if string(apiErr.Body) != tt.want {
+				t.Errorf("Body = %.40q (%d bytes), want %.40q (%d bytes)", apiErr.Body, len(apiErr.Body), tt.want, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package zkwasm
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// redact hides private inputs, signed messages and signatures unless
+// WithSensitiveLogging is set.
+func (h *ZkWasmServiceHelper) redact(v string) string {
+	if h.logSensitive || v == "" {
+		return v
+	}
+
+	return redacted
+}
+
+func (h *ZkWasmServiceHelper) redactInputs(inputs []string) any {
+	if h.logSensitive || len(inputs) == 0 {
+		return inputs
+	}
+
+	return redacted
+}
+
+// logMiddleware logs every attempt of a call to the zkWasm service.
+func (h *ZkWasmServiceHelper) logMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(call *Call) (*CallResult, error) {
+		ctx := call.Request.Context()
+		start := time.Now()
+
+		result, err := next(call)
+
+		attrs := []any{
+			slog.String("endpoint", call.Endpoint),
+			slog.String("method", call.Request.Method),
+			slog.Int("attempt", call.Attempt),
+			slog.Duration("latency", time.Since(start)),
+		}
+		if call.Signature != "" {
+			attrs = append(attrs,
+				slog.String("sign_message", h.redact(call.SignMessage)),
+				slog.String("signature", h.redact(call.Signature)),
+			)
+		}
+
+		if err != nil {
+			h.logger.WarnContext(ctx, "zkwasm request failed", append(attrs, slog.Any("error", err))...)
+			return result, err
+		}
+
+		h.logger.DebugContext(ctx, "zkwasm request", append(attrs, slog.Int("status", result.StatusCode))...)
+		return result, nil
+	}
+}
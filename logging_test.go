@@ -0,0 +1,53 @@
+package zkwasm
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoggingRedaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		sensitive bool
+	}{
+		{name: "redacted", sensitive: false},
+		{name: "sensitive", sensitive: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"success":true,"result":{"md5":"image-md5","id":"task-id"}}`))
+			}, WithLogger(logger), WithSensitiveLogging(tt.sensitive))
+
+			_, err := h.AddProvingTask(context.Background(), &ProvingParams{
+				UserAddress:   h.GetUserAddress(),
+				MD5:           "image-md5",
+				PublicInputs:  []string{"1:i64"},
+				PrivateInputs: []string{"0xdeadbeef:bytes"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			out := buf.String()
+			for _, want := range []string{`"md5":"image-md5"`, `"task_id":"task-id"`, `"endpoint":"/prove"`, `"latency"`} {
+				if !strings.Contains(out, want) {
+					t.Errorf("log does not contain %s:\n%s", want, out)
+				}
+			}
+			if got := strings.Contains(out, "0xdeadbeef"); got != tt.sensitive {
+				t.Errorf("private input logged = %v, want %v:\n%s", got, tt.sensitive, out)
+			}
+			if got := strings.Contains(out, `"signature":"0x`); got != tt.sensitive {
+				t.Errorf("signature logged = %v, want %v:\n%s", got, tt.sensitive, out)
+			}
+		})
+	}
+}
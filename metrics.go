@@ -0,0 +1,51 @@
+package zkwasm
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Metrics receives counters about the helper's activity. See the prommetrics
+// package for a Prometheus implementation.
+type Metrics interface {
+	// ObserveRequest is called for every attempt of a call to the zkWasm
+	// service. status is the HTTP status code, or "error" when no response
+	// was received.
+	ObserveRequest(endpoint, status string, duration time.Duration)
+	// AddImageUploadBytes is called with the size of the image and initial
+	// context of every image added by AddNewWasmImage.
+	AddImageUploadBytes(n int64)
+	// IncProvingTask is called for every task submitted by AddProvingTask.
+	IncProvingTask(md5 string)
+	// IncContractVerify is called for every ContractVerify transaction,
+	// with sent set to false when it failed before the transaction was sent.
+	IncContractVerify(sent bool)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, string, time.Duration) {}
+func (noopMetrics) AddImageUploadBytes(int64)                    {}
+func (noopMetrics) IncProvingTask(string)                        {}
+func (noopMetrics) IncContractVerify(bool)                       {}
+
+func (h *ZkWasmServiceHelper) metricsMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(call *Call) (*CallResult, error) {
+		start := time.Now()
+		result, err := next(call)
+
+		status := "error"
+		var apiErr *APIError
+		switch {
+		case err == nil:
+			status = strconv.Itoa(result.StatusCode)
+		case errors.As(err, &apiErr):
+			status = strconv.Itoa(apiErr.StatusCode)
+		}
+
+		h.metrics.ObserveRequest(call.Endpoint, status, time.Since(start))
+
+		return result, err
+	}
+}
@@ -0,0 +1,100 @@
+package zkwasm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Call is one attempt of a request to the zkWasm service, as seen by
+// middlewares.
+type Call struct {
+	Endpoint string
+	Request  *http.Request
+	// SignMessage and Signature are set for calls signed by the helper's
+	// Signer (AddProvingTask, AddNewWasmImage).
+	SignMessage string
+	Signature   string
+	// Attempt starts at 1 and is incremented on every retry.
+	Attempt int
+}
+
+type CallResult struct {
+	StatusCode int
+	Header     http.Header
+	// Result is the decoded response envelope, a *Response[T] where T is the
+	// result type of the calling method.
+	Result any
+}
+
+type RoundTripFunc func(call *Call) (*CallResult, error)
+
+// Middleware wraps every call the helper makes to the zkWasm service. The
+// innermost RoundTripFunc sends the request and decodes the response, so
+// middlewares see both the outgoing request and the decoded result.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+func (h *ZkWasmServiceHelper) chain(rt RoundTripFunc) RoundTripFunc {
+	rt = h.logMiddleware(rt)
+	rt = h.traceMiddleware(rt)
+	rt = h.metricsMiddleware(rt)
+
+	for i := len(h.middlewares) - 1; i >= 0; i-- {
+		rt = h.middlewares[i](rt)
+	}
+
+	return rt
+}
+
+// HeaderMiddleware sets header on every request, e.g. an API key required by a
+// gateway in front of the service.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*CallResult, error) {
+			for k, vs := range header {
+				call.Request.Header[k] = vs
+			}
+			return next(call)
+		}
+	}
+}
+
+// RequestIDMiddleware sets a random request id in header name on every call
+// which does not carry one yet.
+func RequestIDMiddleware(name string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*CallResult, error) {
+			if call.Request.Header.Get(name) == "" {
+				var b [16]byte
+				if _, err := rand.Read(b[:]); err != nil {
+					return nil, err
+				}
+				call.Request.Header.Set(name, hex.EncodeToString(b[:]))
+			}
+			return next(call)
+		}
+	}
+}
+
+// LoggingMiddleware logs every call with a printf style function such as
+// log.Printf. Signatures are not logged.
+func LoggingMiddleware(logf func(format string, args ...any)) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*CallResult, error) {
+			start := time.Now()
+			result, err := next(call)
+			elapsed := time.Since(start)
+
+			if err != nil {
+				logf("zkwasm %s %s attempt=%d duration=%s error=%v",
+					call.Request.Method, call.Endpoint, call.Attempt, elapsed, err)
+				return result, err
+			}
+
+			logf("zkwasm %s %s attempt=%d duration=%s status=%d",
+				call.Request.Method, call.Endpoint, call.Attempt, elapsed, result.StatusCode)
+			return result, nil
+		}
+	}
+}
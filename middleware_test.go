@@ -0,0 +1,91 @@
+package zkwasm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var gotKey string
+	var order []string
+	var calls []*Call
+	var results []any
+	var logs []string
+
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(call *Call) (*CallResult, error) {
+				order = append(order, name)
+				calls = append(calls, call)
+				result, err := next(call)
+				if err == nil {
+					results = append(results, result.Result)
+				}
+				return result, err
+			}
+		}
+	}
+
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-Api-Key")
+		w.Write([]byte(`{"success":true,"result":{"md5":"md5","id":"task-id"}}`))
+	}, WithMiddleware(
+		record("outer"),
+		HeaderMiddleware(http.Header{"X-Api-Key": {"secret"}}),
+		LoggingMiddleware(func(format string, args ...any) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		}),
+		record("inner"),
+	))
+
+	params := &ProvingParams{UserAddress: h.GetUserAddress(), MD5: "md5"}
+	id, err := h.AddProvingTask(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "task-id" {
+		t.Errorf("id = %q, want %q", id, "task-id")
+	}
+
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("order = %v, want [outer inner]", order)
+	}
+	if gotKey != "secret" {
+		t.Errorf("X-Api-Key = %q, want %q", gotKey, "secret")
+	}
+
+	call := calls[0]
+	if call.Endpoint != endpointProve || call.Attempt != 1 {
+		t.Errorf("call = %s attempt %d, want %s attempt 1", call.Endpoint, call.Attempt, endpointProve)
+	}
+	if call.SignMessage != params.buildSignMessage() || call.Signature == "" {
+		t.Errorf("call.SignMessage = %q, Signature = %q", call.SignMessage, call.Signature)
+	}
+
+	response, ok := results[0].(*Response[*ProvingResult])
+	if !ok || response.Result.ID != "task-id" {
+		t.Errorf("Result = %#v, want *Response[*ProvingResult]", results[0])
+	}
+
+	if len(logs) != 1 || !strings.Contains(logs[0], "status=200") {
+		t.Errorf("logs = %v", logs)
+	}
+}
+
+func TestMiddlewareNilResult(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent past a short-circuiting middleware")
+	}, WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*CallResult, error) {
+			return nil, nil
+		}
+	}))
+
+	_, err := h.LoadTasks(context.Background(), &TaskQueryParams{})
+	if err == nil || !strings.Contains(err.Error(), "no result") {
+		t.Errorf("LoadTasks() err = %v, want a no result error", err)
+	}
+}
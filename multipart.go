@@ -0,0 +1,86 @@
+package zkwasm
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+)
+
+var (
+	ErrImageMD5Required    = errors.New("ImageMD5 is required when ImageReader is not an io.Seeker")
+	ErrImageSourceConflict = errors.New("only one of Image and ImageReader can be set")
+
+	errAttemptDone = errors.New("request attempt finished")
+)
+
+// multipartStream streams a multipart body through an io.Pipe instead of
+// buffering it. The boundary is fixed so that the body can be rebuilt with the
+// same Content-Type for every attempt.
+type multipartStream struct {
+	boundary    string
+	contentType string
+	write       func(w *multipart.Writer) error
+
+	pr   *io.PipeReader
+	done chan struct{}
+}
+
+func newMultipartStream(write func(w *multipart.Writer) error) *multipartStream {
+	w := multipart.NewWriter(io.Discard)
+
+	return &multipartStream{
+		boundary:    w.Boundary(),
+		contentType: w.FormDataContentType(),
+		write:       write,
+	}
+}
+
+// body returns a new reader for the next attempt. It waits for the writer of
+// the previous attempt to stop, so sources can be rewound safely.
+func (s *multipartStream) body() (io.Reader, error) {
+	if s.done != nil {
+		s.pr.CloseWithError(errAttemptDone)
+		<-s.done
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	s.pr, s.done = pr, done
+
+	go func() {
+		defer close(done)
+
+		w := multipart.NewWriter(pw)
+		if err := w.SetBoundary(s.boundary); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		err := s.write(w)
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// rewinder returns a function which moves r back to its current position, and
+// false when r can only be read once.
+func rewinder(r io.Reader) (func() error, bool) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return func() error { return nil }, false
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return func() error { return nil }, false
+	}
+
+	return func() error {
+		_, err := seeker.Seek(start, io.SeekStart)
+		return err
+	}, true
+}
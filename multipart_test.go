@@ -0,0 +1,135 @@
+package zkwasm
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAddNewWasmImageStreaming(t *testing.T) {
+	image := bytes.Repeat([]byte("wasm"), 1<<16)
+	sum := md5.Sum(image)
+	wantMD5 := hex.EncodeToString(sum[:])
+
+	var calls atomic.Int32
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 {
+			t.Errorf("ContentLength = %d, want a streamed body", r.ContentLength)
+		}
+
+		f, _, err := r.FormFile("image")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got, _ := io.ReadAll(f)
+		if !bytes.Equal(got, image) {
+			t.Errorf("image body differs, %d bytes", len(got))
+		}
+		if md5 := r.FormValue("image_md5"); md5 != wantMD5 {
+			t.Errorf("image_md5 = %q, want %q", md5, wantMD5)
+		}
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"id":"image-id","md5":"md5"}}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, RetryWrites: true}))
+
+	id, err := h.AddNewWasmImage(context.Background(), &AddImageParams{
+		Name:        "image.wasm",
+		ImageReader: bytes.NewReader(image),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "image-id" {
+		t.Errorf("id = %q, want %q", id, "image-id")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestAddNewWasmImageOneShotReader(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, RetryWrites: true}))
+
+	_, err := h.AddNewWasmImage(context.Background(), &AddImageParams{
+		Name:        "image.wasm",
+		ImageReader: io.MultiReader(bytes.NewReader([]byte("wasm"))),
+	})
+	if err != ErrImageMD5Required {
+		t.Fatalf("err = %v, want %v", err, ErrImageMD5Required)
+	}
+
+	_, err = h.AddNewWasmImage(context.Background(), &AddImageParams{
+		Name:        "image.wasm",
+		ImageReader: io.MultiReader(bytes.NewReader([]byte("wasm"))),
+		ImageMD5:    "0123456789ABCDEF0123456789ABCDEF",
+	})
+	if err == nil {
+		t.Fatal("err = nil")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestAddNewWasmImageSourceConflict(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("image with two sources was sent")
+	})
+
+	_, err := h.AddNewWasmImage(context.Background(), &AddImageParams{
+		Name:        "image.wasm",
+		Image:       []byte("wasm"),
+		ImageReader: bytes.NewReader([]byte("other wasm")),
+	})
+	if err != ErrImageSourceConflict {
+		t.Errorf("err = %v, want %v", err, ErrImageSourceConflict)
+	}
+}
+
+func TestAddNewWasmImageShortCircuit(t *testing.T) {
+	denied := errors.New("denied")
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent past a short-circuiting middleware")
+	}, WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(call *Call) (*CallResult, error) {
+			return nil, denied
+		}
+	}))
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		_, err := h.AddNewWasmImage(context.Background(), &AddImageParams{
+			Name:        "image.wasm",
+			ImageReader: bytes.NewReader(bytes.Repeat([]byte("wasm"), 1<<12)),
+		})
+		if !errors.Is(err, denied) {
+			t.Fatalf("err = %v, want %v", err, denied)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines left running, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
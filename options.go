@@ -0,0 +1,290 @@
+package zkwasm
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type options struct {
+	endpoints       []string
+	ethEndpoint     string
+	contractAddress string
+
+	httpClient *http.Client
+	timeout    time.Duration
+	userAgent  string
+	headers    http.Header
+
+	retryPolicy *RetryPolicy
+
+	signer Signer
+
+	middlewares []Middleware
+
+	logger       *slog.Logger
+	logSensitive bool
+
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+
+	metrics Metrics
+
+	maxResponseSize int64
+
+	healthCheckInterval time.Duration
+	failureThreshold    int
+
+	discoverConfig bool
+
+	bulkConcurrency int
+}
+
+type Option func(*options) error
+
+func WithBaseURL(zkWasmEndpoint string) Option {
+	return func(o *options) error {
+		o.endpoints = []string{zkWasmEndpoint}
+		return nil
+	}
+}
+
+// WithEndpoints configures several zkWasm endpoints instead of WithBaseURL.
+// The first one is the primary, which receives all writes; reads fail over to
+// the others when it is unhealthy.
+func WithEndpoints(zkWasmEndpoints ...string) Option {
+	return func(o *options) error {
+		o.endpoints = zkWasmEndpoints
+		return nil
+	}
+}
+
+// WithHealthCheck probes every endpoint at interval and marks it unhealthy
+// after failureThreshold consecutive failed probes or requests. Call Close to
+// stop the probes.
+func WithHealthCheck(interval time.Duration, failureThreshold int) Option {
+	return func(o *options) error {
+		o.healthCheckInterval = interval
+		o.failureThreshold = failureThreshold
+		return nil
+	}
+}
+
+func WithEthEndpoint(ethEndpoint string) Option {
+	return func(o *options) error {
+		o.ethEndpoint = strings.TrimSuffix(ethEndpoint, "/")
+		return nil
+	}
+}
+
+// WithPrivateKey uses a hex encoded private key held in memory as the signer.
+func WithPrivateKey(privateKey string) Option {
+	return func(o *options) error {
+		s, err := NewPrivateKeySignerFromHex(privateKey)
+		if err != nil {
+			return err
+		}
+		o.signer = s
+		return nil
+	}
+}
+
+// WithKeystoreFile uses the key of an encrypted V3 keystore file as the signer.
+func WithKeystoreFile(path, passphrase string) Option {
+	return func(o *options) error {
+		s, err := NewKeystoreSignerFromFile(path, passphrase)
+		if err != nil {
+			return err
+		}
+		o.signer = s
+		return nil
+	}
+}
+
+// WithKeystoreDir uses the key of address found in a keystore directory as the
+// signer.
+func WithKeystoreDir(dir string, address common.Address, passphrase string) Option {
+	return func(o *options) error {
+		s, err := NewKeystoreSignerFromDir(dir, address, passphrase)
+		if err != nil {
+			return err
+		}
+		o.signer = s
+		return nil
+	}
+}
+
+// WithMnemonic uses the account derived from a BIP-39 mnemonic at path, e.g.
+// "m/44'/60'/0'/0/0", as the signer.
+func WithMnemonic(mnemonic, passphrase, path string) Option {
+	return func(o *options) error {
+		s, err := NewMnemonicSigner(mnemonic, passphrase, path)
+		if err != nil {
+			return err
+		}
+		o.signer = s
+		return nil
+	}
+}
+
+func WithSigner(s Signer) Option {
+	return func(o *options) error {
+		o.signer = s
+		return nil
+	}
+}
+
+func WithContractAddress(contractAddress string) Option {
+	return func(o *options) error {
+		o.contractAddress = contractAddress
+		return nil
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient for every call to the zkWasm service.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) error {
+		o.httpClient = c
+		return nil
+	}
+}
+
+// WithTimeout bounds each call to the zkWasm service. It is applied on a copy
+// of the configured client, so a client passed to WithHTTPClient is not mutated.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) error {
+		o.timeout = d
+		return nil
+	}
+}
+
+func WithUserAgent(ua string) Option {
+	return func(o *options) error {
+		o.userAgent = ua
+		return nil
+	}
+}
+
+// WithHeaders adds headers to every request. It can be given more than once.
+func WithHeaders(headers http.Header) Option {
+	return func(o *options) error {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		for k, vs := range headers {
+			for _, v := range vs {
+				o.headers.Add(k, v)
+			}
+		}
+		return nil
+	}
+}
+
+// WithMiddleware appends middlewares to the chain wrapping every call to the
+// zkWasm service. The first middleware is the outermost one.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *options) error {
+		o.middlewares = append(o.middlewares, mw...)
+		return nil
+	}
+}
+
+// WithLogger enables structured logging of service calls and eth
+// transactions. Nothing is logged by default.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) error {
+		o.logger = l
+		return nil
+	}
+}
+
+// WithSensitiveLogging disables the redaction of private inputs, signed
+// messages and signatures in logs.
+func WithSensitiveLogging(enabled bool) Option {
+	return func(o *options) error {
+		o.logSensitive = enabled
+		return nil
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider. The global
+// provider is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) error {
+		o.tracerProvider = tp
+		return nil
+	}
+}
+
+// WithPropagator sets the propagator used to inject the trace context into
+// requests. The global propagator is used by default.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(o *options) error {
+		o.propagator = p
+		return nil
+	}
+}
+
+// WithMetrics reports the helper's activity to m, e.g. a prommetrics.Collector.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) error {
+		o.metrics = m
+		return nil
+	}
+}
+
+// WithMaxResponseSize bounds the size of response bodies, larger responses fail
+// with a *ResponseTooLargeError. A negative value disables the limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(o *options) error {
+		o.maxResponseSize = n
+		return nil
+	}
+}
+
+// WithConfigDiscovery fetches the service configuration when the helper is
+// built. It is then used to validate circuit sizes and, when no contract
+// address is given, to find the verifier contract of the eth chain.
+func WithConfigDiscovery() Option {
+	return func(o *options) error {
+		o.discoverConfig = true
+		return nil
+	}
+}
+
+// WithBulkConcurrency bounds the number of concurrent requests of GetTasks.
+// It defaults to DefaultBulkConcurrency.
+func WithBulkConcurrency(n int) Option {
+	return func(o *options) error {
+		o.bulkConcurrency = n
+		return nil
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy. Use NoRetry to disable retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(o *options) error {
+		o.retryPolicy = &p
+		return nil
+	}
+}
+
+func (o *options) buildHTTPClient() *http.Client {
+	c := o.httpClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	if o.timeout > 0 {
+		cc := *c
+		cc.Timeout = o.timeout
+		c = &cc
+	}
+
+	return c
+}
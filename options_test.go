@@ -0,0 +1,105 @@
+package zkwasm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testPrivateKey = "8644db7d9d8beb607960dc23d260d5ac66e8534c41ae77b4d6e22de613d3da2f"
+
+func TestNewWithOptions(t *testing.T) {
+	var gotUA, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		gotKey = r.Header.Get("X-Api-Key")
+		w.Write([]byte(`{"success":true,"result":[]}`))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{}
+	h, err := NewWithOptions(context.Background(),
+		WithBaseURL(srv.URL+"/"),
+		WithEthEndpoint(srv.URL),
+		WithPrivateKey(testPrivateKey),
+		WithHTTPClient(client),
+		WithTimeout(5*time.Second),
+		WithUserAgent("zkwasm-test"),
+		WithHeaders(http.Header{"X-Api-Key": {"secret"}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.Timeout != 0 {
+		t.Errorf("WithTimeout mutated the caller's client")
+	}
+	if h.httpClient.Timeout != 5*time.Second {
+		t.Errorf("httpClient.Timeout = %v, want %v", h.httpClient.Timeout, 5*time.Second)
+	}
+
+	if _, err := h.QueryImage(context.Background(), "md5"); err != nil {
+		t.Fatal(err)
+	}
+	if gotUA != "zkwasm-test" {
+		t.Errorf("User-Agent = %q, want %q", gotUA, "zkwasm-test")
+	}
+	if gotKey != "secret" {
+		t.Errorf("X-Api-Key = %q, want %q", gotKey, "secret")
+	}
+}
+
+func TestNewWithOptionsNoBaseURL(t *testing.T) {
+	_, err := NewWithOptions(context.Background(), WithPrivateKey(testPrivateKey))
+	if err != ErrNoBaseURL {
+		t.Errorf("err = %v, want %v", err, ErrNoBaseURL)
+	}
+}
+
+func newTestHelper(t *testing.T, handler http.HandlerFunc, opts ...Option) *ZkWasmServiceHelper {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts = append([]Option{
+		WithBaseURL(srv.URL),
+		WithEthEndpoint(srv.URL),
+		WithPrivateKey(testPrivateKey),
+	}, opts...)
+
+	h, err := NewWithOptions(context.Background(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func TestNewReadOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	h, err := NewReadOnly(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !h.IsReadOnly() || h.GetUserAddress() != "" {
+		t.Errorf("IsReadOnly() = %v, GetUserAddress() = %q", h.IsReadOnly(), h.GetUserAddress())
+	}
+	if _, err := h.LoadTasks(ctx, &TaskQueryParams{}); err != nil {
+		t.Errorf("LoadTasks() err = %v", err)
+	}
+	if _, err := h.AddProvingTask(ctx, &ProvingParams{}); err != ErrNoSigner {
+		t.Errorf("AddProvingTask() err = %v, want %v", err, ErrNoSigner)
+	}
+	if _, err := h.ContractVerify(ctx, nil, nil, nil, nil, nil); err != ErrNoEthClient {
+		t.Errorf("ContractVerify() err = %v, want %v", err, ErrNoEthClient)
+	}
+}
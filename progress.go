@@ -0,0 +1,71 @@
+package zkwasm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrTransferAborted = errors.New("transfer aborted by progress callback")
+)
+
+// ProgressFunc is called while a transfer is running with the number of bytes
+// transferred so far and the expected total, which is -1 when unknown.
+// Returning an error aborts the transfer, the calling method then returns an
+// error wrapping both ErrTransferAborted and the returned error. Counting
+// restarts from zero when a call is retried.
+type ProgressFunc func(done, total int64) error
+
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func newProgressReader(r io.Reader, total int64, fn ProgressFunc) io.Reader {
+	if fn == nil {
+		return r
+	}
+
+	return &progressReader{r: r, total: total, fn: fn}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		if perr := p.fn(p.done, p.total); perr != nil {
+			// drop the data, decoders may otherwise complete without
+			// looking at the error
+			return 0, fmt.Errorf("%w: %w", ErrTransferAborted, perr)
+		}
+	}
+
+	return n, err
+}
+
+// readerSize returns the number of bytes left in r, or -1 when r is not an
+// io.Seeker.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	}
+
+	return -1
+}
@@ -0,0 +1,80 @@
+package zkwasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestUploadProgress(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"success":true,"result":{"md5":"md5","id":"task-id"}}`))
+	}, WithRetryPolicy(NoRetry()))
+
+	inputContext := bytes.Repeat([]byte{1}, 1<<20)
+
+	var lastDone, lastTotal int64
+	_, err := h.AddProvingTask(context.Background(), &ProvingParams{
+		UserAddress:      h.GetUserAddress(),
+		MD5:              "md5",
+		InputContextType: ProvingParamsInputContextTypeCustom,
+		InputContext:     bytes.NewReader(inputContext),
+		Progress: func(done, total int64) error {
+			lastDone, lastTotal = done, total
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastDone != int64(len(inputContext)) || lastTotal != int64(len(inputContext)) {
+		t.Errorf("progress = %d/%d, want %d/%d", lastDone, lastTotal, len(inputContext), len(inputContext))
+	}
+
+	stop := errors.New("stop")
+	_, err = h.AddProvingTask(context.Background(), &ProvingParams{
+		UserAddress:      h.GetUserAddress(),
+		MD5:              "md5",
+		InputContextType: ProvingParamsInputContextTypeCustom,
+		InputContext:     bytes.NewReader(inputContext),
+		Progress: func(done, total int64) error {
+			return stop
+		},
+	})
+	if !errors.Is(err, ErrTransferAborted) || !errors.Is(err, stop) {
+		t.Errorf("err = %v, want %v wrapping %v", err, ErrTransferAborted, stop)
+	}
+}
+
+func TestDownloadProgress(t *testing.T) {
+	body := []byte(`{"success":true,"result":"AAECAw=="}`)
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+
+	var lastDone, lastTotal int64
+	image, err := h.QueryImageBinaryWithProgress(context.Background(), "md5", func(done, total int64) error {
+		lastDone, lastTotal = done, total
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(image, []byte{0, 1, 2, 3}) {
+		t.Errorf("image = %v", image)
+	}
+	if lastDone != int64(len(body)) || lastTotal != int64(len(body)) {
+		t.Errorf("progress = %d/%d, want %d/%d", lastDone, lastTotal, len(body), len(body))
+	}
+
+	_, err = h.QueryImageBinaryWithProgress(context.Background(), "md5", func(done, total int64) error {
+		return errors.New("stop")
+	})
+	if !errors.Is(err, ErrTransferAborted) {
+		t.Errorf("err = %v, want %v", err, ErrTransferAborted)
+	}
+}
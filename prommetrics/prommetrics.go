@@ -0,0 +1,96 @@
+// Package prommetrics exposes the activity of a zkwasm.ZkWasmServiceHelper as
+// Prometheus metrics.
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	zkwasm "github.com/zkcrossteam/zkWasm-service-helper"
+)
+
+// Collector implements both zkwasm.Metrics and prometheus.Collector.
+type Collector struct {
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	imageUploadBytes  prometheus.Counter
+	provingTasks      *prometheus.CounterVec
+	contractVerifyTxs *prometheus.CounterVec
+}
+
+var _ zkwasm.Metrics = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+func New(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "zkwasm",
+			Name:      "requests_total",
+			Help:      "Requests to the zkWasm service by endpoint and status.",
+		}, []string{"endpoint", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "zkwasm",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests to the zkWasm service by endpoint and status.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"endpoint", "status"}),
+		imageUploadBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "zkwasm",
+			Name:      "image_upload_bytes_total",
+			Help:      "Bytes of wasm images and initial contexts added.",
+		}),
+		provingTasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "zkwasm",
+			Name:      "proving_tasks_total",
+			Help:      "Proving tasks submitted by image md5.",
+		}, []string{"md5"}),
+		contractVerifyTxs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "zkwasm",
+			Name:      "contract_verify_transactions_total",
+			Help:      "Verify transactions by result (sent or failed).",
+		}, []string{"result"}),
+	}
+}
+
+func (c *Collector) ObserveRequest(endpoint, status string, duration time.Duration) {
+	c.requests.WithLabelValues(endpoint, status).Inc()
+	c.requestDuration.WithLabelValues(endpoint, status).Observe(duration.Seconds())
+}
+
+func (c *Collector) AddImageUploadBytes(n int64) {
+	c.imageUploadBytes.Add(float64(n))
+}
+
+func (c *Collector) IncProvingTask(md5 string) {
+	c.provingTasks.WithLabelValues(md5).Inc()
+}
+
+func (c *Collector) IncContractVerify(sent bool) {
+	result := "failed"
+	if sent {
+		result = "sent"
+	}
+	c.contractVerifyTxs.WithLabelValues(result).Inc()
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.requestDuration.Describe(ch)
+	c.imageUploadBytes.Describe(ch)
+	c.provingTasks.Describe(ch)
+	c.contractVerifyTxs.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.requestDuration.Collect(ch)
+	c.imageUploadBytes.Collect(ch)
+	c.provingTasks.Collect(ch)
+	c.contractVerifyTxs.Collect(ch)
+}
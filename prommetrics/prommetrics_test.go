@@ -0,0 +1,88 @@
+package prommetrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	zkwasm "github.com/zkcrossteam/zkWasm-service-helper"
+)
+
+func counterValue(t *testing.T, families []*dto.MetricFamily, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if labels[l.GetName()] != l.GetValue() {
+					continue metrics
+				}
+			}
+			if m.GetHistogram() != nil {
+				return float64(m.GetHistogram().GetSampleCount())
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+
+	return 0
+}
+
+func TestCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("md5") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":[]}`))
+	}))
+	defer srv.Close()
+
+	c := New("test")
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c)
+
+	ctx := context.Background()
+	h, err := zkwasm.NewReadOnly(ctx, srv.URL, zkwasm.WithMetrics(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.QueryImage(ctx, "md5")
+	h.QueryImage(ctx, "md5")
+	h.QueryImage(ctx, "missing")
+	c.IncProvingTask("md5")
+	c.IncContractVerify(true)
+	c.AddImageUploadBytes(1024)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{"test_zkwasm_requests_total", map[string]string{"endpoint": "/image", "status": "200"}, 2},
+		{"test_zkwasm_requests_total", map[string]string{"endpoint": "/image", "status": "404"}, 1},
+		{"test_zkwasm_request_duration_seconds", map[string]string{"endpoint": "/image", "status": "200"}, 2},
+		{"test_zkwasm_image_upload_bytes_total", nil, 1024},
+		{"test_zkwasm_proving_tasks_total", map[string]string{"md5": "md5"}, 1},
+		{"test_zkwasm_contract_verify_transactions_total", map[string]string{"result": "sent"}, 1},
+	}
+	for _, tt := range tests {
+		if got := counterValue(t, families, tt.name, tt.labels); got != tt.want {
+			t.Errorf("%s%v = %v, want %v", tt.name, tt.labels, got, tt.want)
+		}
+	}
+}
@@ -1,11 +1,9 @@
 package zkwasm
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 )
@@ -25,6 +23,9 @@ type ProvingParams struct {
 	InputContextType string    `json:"input_context_type,omitempty"`
 	InputContext     io.Reader `json:"input_context,omitempty"`
 	InputContextMD5  string    `json:"input_context_md5,omitempty"`
+
+	// Progress reports the upload of InputContext.
+	Progress ProgressFunc `json:"-"`
 }
 
 func (p *ProvingParams) buildSignMessage() string {
@@ -58,74 +59,90 @@ type ProvingResult struct {
 }
 
 func (h *ZkWasmServiceHelper) AddProvingTask(ctx context.Context, params *ProvingParams) (string, error) {
+	ctx, span := h.startSpan(ctx, "zkwasm.AddProvingTask", attrImageMD5.String(params.MD5))
+	defer span.End()
+
 	signMsg := params.buildSignMessage()
-	sign, err := h.signMessage(signMsg, false)
+	sign, err := h.signMessage(ctx, signMsg, false)
 	if err != nil {
+		recordError(span, err)
 		return "", err
 	}
 
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
-	w.WriteField("user_address", params.UserAddress)
-	w.WriteField("md5", params.MD5)
-	for _, i := range params.PublicInputs {
-		w.WriteField("public_inputs", i)
-	}
-	for _, i := range params.PrivateInputs {
-		w.WriteField("private_inputs", i)
-	}
-	if params.InputContextType != "" {
-		w.WriteField("input_context_type", params.InputContextType)
-	}
-	if params.InputContextMD5 != "" {
-		w.WriteField("input_context_md5", params.InputContextMD5)
-	}
+	var rewind func() error
+	replayable := true
+	contextSize := int64(-1)
 	if params.InputContext != nil {
-		nw, err := w.CreateFormField("input_context")
-		if err != nil {
-			return "", err
-		}
-		_, err = io.Copy(nw, params.InputContext)
-		if err != nil {
-			return "", err
-		}
-	}
-	err = w.Close()
-	if err != nil {
-		return "", err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.zkWasmEndpoint+endpointProve, &b)
-	if err != nil {
-		return "", err
+		rewind, replayable = rewinder(params.InputContext)
+		contextSize = readerSize(params.InputContext)
 	}
 
-	req.Header[headerSignatureKey] = []string{sign}
-	req.Header.Add("Content-Type", w.FormDataContentType())
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
+	stream := newMultipartStream(func(w *multipart.Writer) error {
+		w.WriteField("user_address", params.UserAddress)
+		w.WriteField("md5", params.MD5)
+		for _, i := range params.PublicInputs {
+			w.WriteField("public_inputs", i)
+		}
+		for _, i := range params.PrivateInputs {
+			w.WriteField("private_inputs", i)
+		}
+		if params.InputContextType != "" {
+			w.WriteField("input_context_type", params.InputContextType)
+		}
+		if params.InputContextMD5 != "" {
+			w.WriteField("input_context_md5", params.InputContextMD5)
+		}
+		if params.InputContext != nil {
+			if err := rewind(); err != nil {
+				return err
+			}
+			nw, err := w.CreateFormField("input_context")
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(nw, newProgressReader(params.InputContext, contextSize, params.Progress))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	header := make(http.Header)
+	header[headerSignatureKey] = []string{sign}
+	header.Set("Content-Type", stream.contentType)
+
+	result, err := doRequest[*ProvingResult](ctx, h, &apiCall{
+		method:   http.MethodPost,
+		endpoint: endpointProve,
+		header:   header,
+		body:     stream.body,
+		write:    true,
+		oneShot:  !replayable,
+
+		signMessage: signMsg,
+		signature:   sign,
+	})
+	if err == nil && result == nil {
+		err = errEmptyResult(endpointProve)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "add proving task failed",
+			slog.String("md5", params.MD5),
+			slog.Any("error", err),
+		)
 		return "", err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(string(body))
-	}
-
-	result := &Response[*ProvingResult]{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return "", err
-	}
-
-	if !result.Success || result.Result == nil {
-		return "", errors.New(string(body))
-	}
+	span.SetAttributes(attrTaskID.String(result.ID))
+	h.metrics.IncProvingTask(params.MD5)
+	h.logger.InfoContext(ctx, "added proving task",
+		slog.String("md5", params.MD5),
+		slog.String("task_id", result.ID),
+		slog.Any("public_inputs", params.PublicInputs),
+		slog.Any("private_inputs", h.redactInputs(params.PrivateInputs)),
+	)
 
-	return result.Result.ID, nil
+	return result.ID, nil
 }
@@ -0,0 +1,225 @@
+package zkwasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// apiCall describes one request to the zkWasm service. The body is built by a
+// function so that the request can be rebuilt for every attempt.
+type apiCall struct {
+	method   string
+	endpoint string
+	query    url.Values
+	header   http.Header
+	body     func() (io.Reader, error)
+
+	signMessage string
+	signature   string
+
+	// write marks calls which change state on the service. They are only
+	// retried when the request never reached the server, unless the retry
+	// policy opts in.
+	write bool
+	// oneShot marks calls whose body can only be read once. They are never
+	// retried.
+	oneShot bool
+
+	// downloadProgress is called while the response body is read.
+	downloadProgress ProgressFunc
+}
+
+func (h *ZkWasmServiceHelper) newRequest(ctx context.Context, baseURL string, c *apiCall) (*http.Request, error) {
+	var body io.Reader
+	if c.body != nil {
+		b, err := c.body()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, baseURL+c.endpoint, body)
+	if err != nil {
+		if closer, ok := body.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, err
+	}
+
+	if len(c.query) != 0 {
+		req.URL.RawQuery = c.query.Encode()
+	}
+
+	for k, vs := range h.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	if h.userAgent != "" {
+		req.Header.Set("User-Agent", h.userAgent)
+	}
+
+	for k, vs := range c.header {
+		req.Header[k] = vs
+	}
+
+	return req, nil
+}
+
+// doRequest sends c and decodes the Response envelope, failing over to other
+// endpoints for reads and retrying according to the helper's RetryPolicy. Any
+// non-200 status or success=false is turned into an *APIError.
+func doRequest[T any](ctx context.Context, h *ZkWasmServiceHelper, c *apiCall) (T, error) {
+	tried := make(map[string]bool)
+
+	for attempt := 1; ; attempt++ {
+		baseURL := h.endpoints.pick(c.write, tried)
+		tried[baseURL] = true
+
+		result, err := doOnce[T](ctx, h, baseURL, c, attempt)
+		if err == nil {
+			h.endpoints.report(baseURL, true)
+			return result, nil
+		}
+
+		endpointFailure := isEndpointFailure(ctx, err)
+		if endpointFailure {
+			h.endpoints.report(baseURL, false)
+		}
+
+		if c.oneShot {
+			return result, err
+		}
+
+		if endpointFailure && h.endpoints.untried(c.write, tried) {
+			h.logger.WarnContext(ctx, "failing over zkwasm request",
+				slog.String("endpoint", c.endpoint),
+				slog.String("from", baseURL),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		delay, ok := h.retryPolicy.retryDelay(ctx, attempt, c.write, err)
+		if !ok {
+			return result, err
+		}
+
+		h.logger.InfoContext(ctx, "retrying zkwasm request",
+			slog.String("endpoint", c.endpoint),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return result, err
+		}
+	}
+}
+
+func doOnce[T any](ctx context.Context, h *ZkWasmServiceHelper, baseURL string, c *apiCall, attempt int) (T, error) {
+	var zero T
+
+	req, err := h.newRequest(ctx, baseURL, c)
+	if err != nil {
+		return zero, err
+	}
+	// The transport closes the body, but a middleware may return without
+	// calling it. Closing it again stops a streaming body's writer.
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	rt := h.chain(func(call *Call) (*CallResult, error) {
+		return roundTrip[T](h, c, call)
+	})
+
+	result, err := rt(&Call{
+		Endpoint:    c.endpoint,
+		Request:     req,
+		SignMessage: c.signMessage,
+		Signature:   c.signature,
+		Attempt:     attempt,
+	})
+	if err != nil {
+		return zero, err
+	}
+	if result == nil {
+		return zero, fmt.Errorf("zkwasm %s: middleware returned no result", c.endpoint)
+	}
+
+	response, ok := result.Result.(*Response[T])
+	if !ok {
+		return zero, fmt.Errorf("zkwasm %s: unexpected result type %T", c.endpoint, result.Result)
+	}
+
+	return response.Result, nil
+}
+
+// roundTrip is the innermost RoundTripFunc: it sends the request and decodes
+// the response envelope.
+func roundTrip[T any](h *ZkWasmServiceHelper, c *apiCall, call *Call) (*CallResult, error) {
+	resp, err := h.httpClient.Do(call.Request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tooLarge := &ResponseTooLargeError{Endpoint: call.Endpoint, Limit: h.maxResponseSize}
+	if h.maxResponseSize > 0 && resp.ContentLength > h.maxResponseSize {
+		return nil, tooLarge
+	}
+
+	body := newProgressReader(resp.Body, resp.ContentLength, c.downloadProgress)
+	body = newMaxBytesReader(body, h.maxResponseSize, tooLarge)
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+		if err != nil && !errors.Is(err, ErrResponseTooLarge) {
+			return nil, err
+		}
+
+		apiErr := newAPIError(call.Endpoint, resp.StatusCode, b)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, apiErr
+	}
+
+	// keep the head of the body for the APIError of a success=false response
+	head := &headBuffer{n: maxErrorBodySize}
+	response := &Response[T]{}
+	if err := json.NewDecoder(io.TeeReader(body, head)).Decode(response); err != nil {
+		if errors.Is(err, ErrResponseTooLarge) || errors.Is(err, ErrTransferAborted) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("zkwasm %s: decode response: %w", call.Endpoint, err)
+	}
+
+	if !response.Success {
+		apiErr := newResponseAPIError(call.Endpoint, resp.StatusCode, response.Error)
+		apiErr.Body = head.buf
+		return nil, apiErr
+	}
+
+	return &CallResult{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Result:     response,
+	}, nil
+}
+
+func errEmptyResult(endpoint string) error {
+	return &APIError{
+		StatusCode: http.StatusOK,
+		Endpoint:   endpoint,
+		Message:    "empty result",
+	}
+}
@@ -0,0 +1,157 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. A call whose Retry-After
+	// asks for a longer delay is not retried.
+	MaxBackoff time.Duration
+	Multiplier float64
+	// Jitter is the fraction of each backoff that is randomized, in [0, 1].
+	Jitter float64
+
+	// RetryWrites allows AddProvingTask and AddNewWasmImage to be retried
+	// after the request may have reached the server. This can create
+	// duplicate tasks or images.
+	RetryWrites bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	if p.Multiplier > 1 {
+		d *= math.Pow(p.Multiplier, float64(attempt-1))
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		d -= d * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// retryDelay reports whether a call which failed with err on the given
+// attempt should be retried, and how long to wait before doing so.
+func (p RetryPolicy) retryDelay(ctx context.Context, attempt int, write bool, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if write && !p.RetryWrites {
+			return 0, false
+		}
+		if !apiErr.IsServerError() && apiErr.StatusCode != http.StatusTooManyRequests {
+			return 0, false
+		}
+		if apiErr.RetryAfter > 0 {
+			if p.MaxBackoff > 0 && apiErr.RetryAfter > p.MaxBackoff {
+				return 0, false
+			}
+			return apiErr.RetryAfter, true
+		}
+		return p.backoff(attempt), true
+	}
+
+	if isNotSentError(err) {
+		return p.backoff(attempt), true
+	}
+
+	if (!write || p.RetryWrites) && isTransientNetError(err) {
+		return p.backoff(attempt), true
+	}
+
+	return 0, false
+}
+
+// isNotSentError reports whether err happened before the request could reach
+// the server, which makes it safe to retry any call.
+func isNotSentError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func isTransientNetError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+	}
+}
+
+func TestRetryReads(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	}, WithRetryPolicy(testRetryPolicy()))
+
+	if _, err := h.LoadTasks(context.Background(), &TaskQueryParams{ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetryAfterOverMaxBackoff(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetryPolicy(testRetryPolicy()))
+
+	start := time.Now()
+	_, err := h.LoadTasks(context.Background(), &TaskQueryParams{ID: "1"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Hour {
+		t.Fatalf("err = %v, want an *APIError with RetryAfter 1h", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("LoadTasks() took %s", d)
+	}
+}
+
+func TestRetryWrites(t *testing.T) {
+	tests := []struct {
+		name        string
+		retryWrites bool
+		wantCalls   int32
+	}{
+		{name: "not retried by default", retryWrites: false, wantCalls: 1},
+		{name: "retried when opted in", retryWrites: true, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			p := testRetryPolicy()
+			p.RetryWrites = tt.retryWrites
+			h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}, WithRetryPolicy(p))
+
+			_, err := h.AddProvingTask(context.Background(), &ProvingParams{MD5: "md5"})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("err = %v, want 503 APIError", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryNotOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}, WithRetryPolicy(testRetryPolicy()))
+
+	if _, err := h.QueryImage(context.Background(), "md5"); err == nil {
+		t.Fatal("err = nil")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"garbage", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
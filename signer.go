@@ -0,0 +1,197 @@
+package zkwasm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer is the identity used to sign service requests and verify
+// transactions.
+type Signer interface {
+	Address() common.Address
+	// SignText signs message as an EIP-191 personal message. The returned
+	// signature is [R || S || V] with V in {0, 1}.
+	SignText(ctx context.Context, message []byte) ([]byte, error)
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+var (
+	ErrSignerAddressMismatch = errors.New("signer returned a transaction from another address")
+	ErrNoSignerAccounts      = errors.New("external signer has no accounts")
+)
+
+// PrivateKeySigner keeps the private key in process memory.
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func NewPrivateKeySignerFromHex(privateKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKeySigner(key), nil
+}
+
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *PrivateKeySigner) SignText(_ context.Context, message []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(message), s.key)
+}
+
+func (s *PrivateKeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewLondonSigner(chainID), s.key)
+}
+
+// ExternalSigner delegates signing to a Clef compatible signer daemon over
+// JSON-RPC (account_signData / account_signTransaction), so the key never
+// enters this process.
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewExternalSigner dials the signer at endpoint (http, ws or ipc path). When
+// address is the zero address the first account reported by the signer is
+// used.
+func NewExternalSigner(ctx context.Context, endpoint string, address common.Address) (*ExternalSigner, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := NewExternalSignerWithClient(ctx, client, address)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func NewExternalSignerWithClient(ctx context.Context, client *rpc.Client, address common.Address) (*ExternalSigner, error) {
+	s := &ExternalSigner{
+		client:  client,
+		address: address,
+	}
+
+	if address == (common.Address{}) {
+		var addresses []common.Address
+		if err := client.CallContext(ctx, &addresses, "account_list"); err != nil {
+			return nil, err
+		}
+		if len(addresses) == 0 {
+			return nil, ErrNoSignerAccounts
+		}
+		s.address = addresses[0]
+	}
+
+	return s, nil
+}
+
+func (s *ExternalSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *ExternalSigner) SignText(ctx context.Context, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	address := common.NewMixedcaseAddress(s.address)
+	if err := s.client.CallContext(ctx, &signature, "account_signData",
+		accounts.MimetypeTextPlain, &address, hexutil.Encode(message)); err != nil {
+		return nil, err
+	}
+
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("external signer returned a %d byte signature", len(signature))
+	}
+
+	// clef returns V in the legacy 27/28 form
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+
+	return signature, nil
+}
+
+func (s *ExternalSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	var to *common.MixedcaseAddress
+	if tx.To() != nil {
+		t := common.NewMixedcaseAddress(*tx.To())
+		to = &t
+	}
+
+	args := &apitypes.SendTxArgs{
+		Data:  &data,
+		Nonce: hexutil.Uint64(tx.Nonce()),
+		Value: hexutil.Big(*tx.Value()),
+		Gas:   hexutil.Uint64(tx.Gas()),
+		To:    to,
+		From:  common.NewMixedcaseAddress(s.address),
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("unsupported tx type %d", tx.Type())
+	}
+
+	if chainID != nil && chainID.Sign() != 0 {
+		args.ChainID = (*hexutil.Big)(chainID)
+	}
+	if tx.Type() != types.LegacyTxType {
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	}
+
+	var res struct {
+		Raw hexutil.Bytes      `json:"raw"`
+		Tx  *types.Transaction `json:"tx"`
+	}
+	if err := s.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return nil, err
+	}
+	if res.Tx == nil {
+		return nil, errors.New("external signer returned no transaction")
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(res.Tx.ChainId()), res.Tx)
+	if err != nil {
+		return nil, err
+	}
+	if from != s.address {
+		return nil, ErrSignerAddressMismatch
+	}
+
+	return res.Tx, nil
+}
+
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
@@ -0,0 +1,111 @@
+package zkwasm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// fakeClef implements the subset of the clef external API used by ExternalSigner.
+type fakeClef struct {
+	signer *PrivateKeySigner
+}
+
+func (c *fakeClef) List() []common.Address {
+	return []common.Address{c.signer.Address()}
+}
+
+func (c *fakeClef) SignData(ctx context.Context, contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	sig, err := c.signer.SignText(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+func (c *fakeClef) SignTransaction(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (map[string]any, error) {
+	tx, err := c.signer.SignTx(ctx, args.ToTransaction(), (*big.Int)(args.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"raw": hexutil.Bytes(raw), "tx": tx}, nil
+}
+
+func testSigners(t *testing.T) []Signer {
+	t.Helper()
+
+	local, err := NewPrivateKeySignerFromHex(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &fakeClef{signer: local}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+
+	external, err := NewExternalSignerWithClient(context.Background(), rpc.DialInProc(server), common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(external.Close)
+
+	return []Signer{local, external}
+}
+
+func TestSigner(t *testing.T) {
+	ctx := context.Background()
+	want := common.HexToAddress("0x582867abf63EbfA5803327a960AC381C2E01d67b")
+	chainID := big.NewInt(5)
+
+	for _, s := range testSigners(t) {
+		if s.Address() != want {
+			t.Errorf("%T.Address() = %s, want %s", s, s.Address(), want)
+		}
+
+		message := []byte("hello zkwasm")
+		sig, err := s.SignText(ctx, message)
+		if err != nil {
+			t.Fatalf("%T.SignText() err = %v", s, err)
+		}
+		pub, err := crypto.SigToPub(accounts.TextHash(message), sig)
+		if err != nil {
+			t.Fatalf("%T.SignText() returned invalid signature: %v", s, err)
+		}
+		if got := crypto.PubkeyToAddress(*pub); got != want {
+			t.Errorf("%T.SignText() recovered %s, want %s", s, got, want)
+		}
+
+		to := common.HexToAddress("0x9D48Dce80682108864F1FB719229DCd0C45E51D7")
+		tx, err := s.SignTx(ctx, types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     1,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(0),
+		}), chainID)
+		if err != nil {
+			t.Fatalf("%T.SignTx() err = %v", s, err)
+		}
+		from, err := types.Sender(types.NewLondonSigner(chainID), tx)
+		if err != nil || from != want {
+			t.Errorf("%T.SignTx() sender = %s, %v, want %s", s, from, err, want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package zkwasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownStatus = errors.New("unknown status")
+
+// TaskStatus is the status of a proving task. Statuses the SDK does not
+// know are kept as is when decoded; IsKnown reports them.
+type TaskStatus string
+
+const (
+	TaskStatusPending       TaskStatus = "Pending"
+	TaskStatusProcessing    TaskStatus = "Processing"
+	TaskStatusDryRunSuccess TaskStatus = "DryRunSuccess"
+	TaskStatusDryRunFailed  TaskStatus = "DryRunFailed"
+	TaskStatusDone          TaskStatus = "Done"
+	TaskStatusFail          TaskStatus = "Fail"
+	TaskStatusStale         TaskStatus = "Stale"
+)
+
+// taskTransitions lists the statuses each non-terminal status can move to.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending:       {TaskStatusDryRunSuccess, TaskStatusDryRunFailed, TaskStatusProcessing, TaskStatusFail, TaskStatusStale},
+	TaskStatusDryRunSuccess: {TaskStatusProcessing, TaskStatusFail, TaskStatusStale},
+	TaskStatusProcessing:    {TaskStatusDone, TaskStatusFail, TaskStatusStale},
+}
+
+// ParseTaskStatus returns ErrUnknownStatus for statuses the SDK does not know.
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	status := TaskStatus(s)
+	if !status.IsKnown() {
+		return "", fmt.Errorf("%w: task status %q", ErrUnknownStatus, s)
+	}
+	return status, nil
+}
+
+func (s TaskStatus) IsKnown() bool {
+	switch s {
+	case TaskStatusPending, TaskStatusProcessing, TaskStatusDryRunSuccess,
+		TaskStatusDryRunFailed, TaskStatusDone, TaskStatusFail, TaskStatusStale:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the task will not change status any more.
+func (s TaskStatus) IsTerminal() bool {
+	return s.IsSuccess() || s.IsFailure()
+}
+
+func (s TaskStatus) IsSuccess() bool {
+	return s == TaskStatusDone
+}
+
+func (s TaskStatus) IsFailure() bool {
+	return s == TaskStatusFail || s == TaskStatusDryRunFailed || s == TaskStatusStale
+}
+
+// CanTransitionTo reports whether the service moves a task from s to next.
+// Staying in the same status is allowed; transitions involving unknown
+// statuses are not.
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	if !s.IsKnown() || !next.IsKnown() {
+		return false
+	}
+	if s == next {
+		return true
+	}
+	for _, to := range taskTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s TaskStatus) String() string {
+	return string(s)
+}
+
+func (s TaskStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+func (s *TaskStatus) UnmarshalText(text []byte) error {
+	*s = TaskStatus(text)
+	return nil
+}
+
+// ImageStatus is the status of a wasm image. Statuses the SDK does not know
+// are kept as is when decoded; IsKnown reports them.
+type ImageStatus string
+
+const (
+	ImageStatusReceived    ImageStatus = "Received"
+	ImageStatusInitialized ImageStatus = "Initialized"
+	ImageStatusVerified    ImageStatus = "Verified"
+)
+
+var imageTransitions = map[ImageStatus][]ImageStatus{
+	ImageStatusReceived:    {ImageStatusInitialized},
+	ImageStatusInitialized: {ImageStatusVerified},
+}
+
+// ParseImageStatus returns ErrUnknownStatus for statuses the SDK does not know.
+func ParseImageStatus(s string) (ImageStatus, error) {
+	status := ImageStatus(s)
+	if !status.IsKnown() {
+		return "", fmt.Errorf("%w: image status %q", ErrUnknownStatus, s)
+	}
+	return status, nil
+}
+
+func (s ImageStatus) IsKnown() bool {
+	switch s {
+	case ImageStatusReceived, ImageStatusInitialized, ImageStatusVerified:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the image will not change status any more.
+func (s ImageStatus) IsTerminal() bool {
+	return s.IsSuccess() || s.IsFailure()
+}
+
+// IsSuccess reports whether the image is verified and can be used for
+// proving.
+func (s ImageStatus) IsSuccess() bool {
+	return s == ImageStatusVerified
+}
+
+// IsFailure is false for every known image status; the service reports
+// failed setups as errors instead.
+func (s ImageStatus) IsFailure() bool {
+	return false
+}
+
+// CanTransitionTo reports whether the service moves an image from s to
+// next. Staying in the same status is allowed; transitions involving
+// unknown statuses are not.
+func (s ImageStatus) CanTransitionTo(next ImageStatus) bool {
+	if !s.IsKnown() || !next.IsKnown() {
+		return false
+	}
+	if s == next {
+		return true
+	}
+	for _, to := range imageTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ImageStatus) String() string {
+	return string(s)
+}
+
+func (s ImageStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+func (s *ImageStatus) UnmarshalText(text []byte) error {
+	*s = ImageStatus(text)
+	return nil
+}
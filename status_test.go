@@ -0,0 +1,105 @@
+package zkwasm
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTaskStatus(t *testing.T) {
+	tests := []struct {
+		status                         TaskStatus
+		known, terminal, success, fail bool
+	}{
+		{TaskStatusPending, true, false, false, false},
+		{TaskStatusProcessing, true, false, false, false},
+		{TaskStatusDryRunSuccess, true, false, false, false},
+		{TaskStatusDryRunFailed, true, true, false, true},
+		{TaskStatusDone, true, true, true, false},
+		{TaskStatusFail, true, true, false, true},
+		{TaskStatusStale, true, true, false, true},
+		{"Archived", false, false, false, false},
+		{"", false, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsKnown(); got != tt.known {
+				t.Errorf("IsKnown() = %v, want %v", got, tt.known)
+			}
+			if got := tt.status.IsTerminal(); got != tt.terminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.terminal)
+			}
+			if got := tt.status.IsSuccess(); got != tt.success {
+				t.Errorf("IsSuccess() = %v, want %v", got, tt.success)
+			}
+			if got := tt.status.IsFailure(); got != tt.fail {
+				t.Errorf("IsFailure() = %v, want %v", got, tt.fail)
+			}
+			if _, err := ParseTaskStatus(string(tt.status)); (err == nil) != tt.known {
+				t.Errorf("ParseTaskStatus() err = %v", err)
+			} else if err != nil && !errors.Is(err, ErrUnknownStatus) {
+				t.Errorf("ParseTaskStatus() err = %v, want %v", err, ErrUnknownStatus)
+			}
+		})
+	}
+}
+
+func TestTaskStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to TaskStatus
+		want     bool
+	}{
+		{TaskStatusPending, TaskStatusPending, true},
+		{TaskStatusPending, TaskStatusProcessing, true},
+		{TaskStatusPending, TaskStatusDryRunFailed, true},
+		{TaskStatusDryRunSuccess, TaskStatusProcessing, true},
+		{TaskStatusProcessing, TaskStatusDone, true},
+		{TaskStatusProcessing, TaskStatusFail, true},
+		{TaskStatusProcessing, TaskStatusPending, false},
+		{TaskStatusPending, TaskStatusDone, false},
+		{TaskStatusDone, TaskStatusProcessing, false},
+		{TaskStatusFail, TaskStatusDone, false},
+		{TaskStatusPending, "Archived", false},
+		{"Archived", "Archived", false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%q.CanTransitionTo(%q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestImageStatus(t *testing.T) {
+	if !ImageStatusReceived.CanTransitionTo(ImageStatusInitialized) || ImageStatusVerified.CanTransitionTo(ImageStatusReceived) {
+		t.Errorf("unexpected image status transitions")
+	}
+	if !ImageStatusVerified.IsTerminal() || !ImageStatusVerified.IsSuccess() || ImageStatusReceived.IsTerminal() {
+		t.Errorf("unexpected image status lifecycle")
+	}
+	if _, err := ParseImageStatus("Broken"); !errors.Is(err, ErrUnknownStatus) {
+		t.Errorf("ParseImageStatus() err = %v, want %v", err, ErrUnknownStatus)
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	var task Task
+	if err := json.Unmarshal([]byte(`{"id":"1","status":"Archived"}`), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != "Archived" || task.Status.IsKnown() {
+		t.Errorf("Status = %q, want the unknown status kept", task.Status)
+	}
+
+	var image Image
+	if err := json.Unmarshal([]byte(`{"status":"Verified"}`), &image); err != nil {
+		t.Fatal(err)
+	}
+	if image.Status != ImageStatusVerified {
+		t.Errorf("Status = %q, want %q", image.Status, ImageStatusVerified)
+	}
+
+	b, err := json.Marshal(struct{ Status TaskStatus }{TaskStatusDone})
+	if err != nil || string(b) != `{"Status":"Done"}` {
+		t.Errorf("Marshal() = %s, %v", b, err)
+	}
+}
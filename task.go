@@ -3,113 +3,196 @@ package zkwasm
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"io"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"strconv"
-)
+	"time"
 
-const (
-	TaskStatusPending       = "Pending"
-	TaskStatusProcessing    = "Processing"
-	TaskStatusDryRunSuccess = "DryRunSuccess"
-	TaskStatusDryRunFailed  = "DryRunFailed"
-	TaskStatusDone          = "Done"
-	TaskStatusFail          = "Fail"
-	TaskStatusStale         = "Stale"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type TaskQueryParams struct {
-	UserAddress string `json:",omitempty"`
-	MD5         string `json:"md5,omitempty"`
-	ID          string `json:"id,omitempty"`
-	TaskType    string `json:"tasktype,omitempty"`
-	TaskStatus  string `json:"taskstatus,omitempty"`
-	Start       int64  `json:"start,omitempty"`
-	Total       int64  `json:"total,omitempty"`
+	UserAddress  string     `json:"user_address,omitempty"`
+	MD5          string     `json:"md5,omitempty"`
+	ID           string     `json:"id,omitempty"`
+	TaskType     string     `json:"tasktype,omitempty"`
+	TaskStatus   TaskStatus `json:"taskstatus,omitempty"`
+	SubmitAfter  time.Time  `json:"submit_after,omitempty"`
+	SubmitBefore time.Time  `json:"submit_before,omitempty"`
+	Start        int64      `json:"start,omitempty"`
+	Total        int64      `json:"total,omitempty"`
 }
 
 type Task struct {
-	UserAddress       string   `json:"user_address"`
-	NodeAddress       string   `json:"node_address"`
-	MD5               string   `json:"md5,omitempty"`
-	TaskType          string   `json:"task_type,omitempty"`
-	Status            string   `json:"status"`
-	SingleProof       []byte   `json:"single_proof"`
-	Proof             []byte   `json:"proof"`
-	Aux               []byte   `json:"aux"`
-	ExternalHostTable []byte   `json:"external_host_table"`
-	ShadowInstances   []byte   `json:"shadow_instances"`
-	BatchInstances    []byte   `json:"batch_instances"`
-	Instances         []byte   `json:"instances"`
-	PublicInputs      []string `json:"public_inputs"`
-	PrivateInputs     []string `json:"private_inputs"`
-	InputContext      []byte
-	InputContextType  string
-	OutputContext     []byte
-	ID                string `json:"id"`
-	SubmitTime        string
-	ProcessStarted    string
-	ProcessFinished   string
-	TaskFee           []byte `json:"task_fee"`
-	StatusMessage     string
-	InternalMessage   string
-	// task_verification_data: TaskVerificationData;
-	DebugLogs       string
-	ProofSubmitMode string `json:"proof_submit_mode"`
-	// batch_proof_data?: BatchProofData;
-	AutoSubmitStatus string `json:"auto_submit_status"`
+	UserAddress          string     `json:"user_address"`
+	NodeAddress          string     `json:"node_address"`
+	MD5                  string     `json:"md5,omitempty"`
+	TaskType             string     `json:"task_type,omitempty"`
+	Status               TaskStatus `json:"status"`
+	SingleProof          []byte     `json:"single_proof"`
+	Proof                []byte     `json:"proof"`
+	Aux                  []byte     `json:"aux"`
+	ExternalHostTable    []byte     `json:"external_host_table"`
+	ShadowInstances      []byte     `json:"shadow_instances"`
+	BatchInstances       []byte     `json:"batch_instances"`
+	Instances            []byte     `json:"instances"`
+	PublicInputs         []string   `json:"public_inputs"`
+	PrivateInputs        []string   `json:"private_inputs"`
+	InputContext         []byte
+	InputContextType     string
+	OutputContext        []byte
+	ID                   string     `json:"id"`
+	SubmitTime           time.Time  `json:"submit_time"`
+	ProcessStarted       *time.Time `json:"process_started,omitempty"`
+	ProcessFinished      *time.Time `json:"process_finished,omitempty"`
+	TaskFee              []byte     `json:"task_fee"`
+	StatusMessage        string
+	InternalMessage      string
+	TaskVerificationData *TaskVerificationData `json:"task_verification_data,omitempty"`
+	DebugLogs            string
+	ProofSubmitMode      string          `json:"proof_submit_mode"`
+	BatchProofData       *BatchProofData `json:"batch_proof_data,omitempty"`
+	AutoSubmitStatus     string          `json:"auto_submit_status"`
 }
 
-func (h *ZkWasmServiceHelper) LoadTasks(ctx context.Context, query *TaskQueryParams) (*PaginationResult[*Task], error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.zkWasmEndpoint+endpointTasks, nil)
-	if err != nil {
-		return nil, err
+// UnmarshalJSON decodes the timestamps, which the service sends as RFC 3339
+// strings, strings without a zone (UTC) or unix milliseconds.
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type task Task
+	aux := struct {
+		*task
+		SubmitTime      json.RawMessage `json:"submit_time"`
+		ProcessStarted  json.RawMessage `json:"process_started"`
+		ProcessFinished json.RawMessage `json:"process_finished"`
+	}{task: (*task)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
 	}
 
-	q := req.URL.Query()
-	if query.ID != "" {
-		q.Add("id", query.ID)
+	submitted, err := parseTimestamp(aux.SubmitTime)
+	if err != nil {
+		return fmt.Errorf("submit_time: %w", err)
 	}
-	if query.Total != 0 {
-		q.Add("total", strconv.FormatInt(query.Total, 10))
+	if submitted != nil {
+		t.SubmitTime = *submitted
 	}
-	if query.MD5 != "" {
-		q.Add("md5", query.MD5)
+	if t.ProcessStarted, err = parseTimestamp(aux.ProcessStarted); err != nil {
+		return fmt.Errorf("process_started: %w", err)
 	}
-	if query.TaskType != "" {
-		q.Add("tasktype", query.TaskType)
+	if t.ProcessFinished, err = parseTimestamp(aux.ProcessFinished); err != nil {
+		return fmt.Errorf("process_finished: %w", err)
 	}
-	if query.TaskStatus != "" {
-		q.Add("taskstatus", query.TaskStatus)
+
+	return nil
+}
+
+// parseTimestamp returns nil for a missing, null or empty timestamp.
+func parseTimestamp(raw json.RawMessage) (*time.Time, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
 	}
-	// TODO: other params
 
-	req.URL.RawQuery = q.Encode()
+	var ms int64
+	if err := json.Unmarshal(raw, &ms); err == nil {
+		ts := time.UnixMilli(ms).UTC()
+		return &ts, nil
+	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	if v == "" {
+		return nil, nil
+	}
+	ts, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		ts, err = time.Parse("2006-01-02T15:04:05.999999999", v)
+	}
 	if err != nil {
 		return nil, err
 	}
+	return &ts, nil
+}
+
+// QueueDuration is the time from submission until proving started, or 0
+// if the task has not started.
+func (t *Task) QueueDuration() time.Duration {
+	if t.ProcessStarted == nil || t.SubmitTime.IsZero() {
+		return 0
+	}
+	return t.ProcessStarted.Sub(t.SubmitTime)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(string(body))
+// ProvingDuration is the time from the start to the end of proving, or 0
+// if the task has not finished.
+func (t *Task) ProvingDuration() time.Duration {
+	if t.ProcessStarted == nil || t.ProcessFinished == nil {
+		return 0
 	}
+	return t.ProcessFinished.Sub(*t.ProcessStarted)
+}
+
+// TotalDuration is the time from submission until the task finished, or 0
+// if the task has not finished.
+func (t *Task) TotalDuration() time.Duration {
+	if t.ProcessFinished == nil || t.SubmitTime.IsZero() {
+		return 0
+	}
+	return t.ProcessFinished.Sub(t.SubmitTime)
+}
 
-	result := &Response[*PaginationResult[*Task]]{}
-	if err := json.Unmarshal(body, result); err != nil {
+func (h *ZkWasmServiceHelper) LoadTasks(ctx context.Context, query *TaskQueryParams) (*PaginationResult[*Task], error) {
+	ctx, span := h.startSpan(ctx, "zkwasm.LoadTasks",
+		attrTaskID.String(query.ID),
+		attrImageMD5.String(query.MD5),
+	)
+	defer span.End()
+
+	if err := query.Validate(); err != nil {
+		recordError(span, err)
 		return nil, err
 	}
 
-	if !result.Success || result.Result == nil {
-		return nil, errors.New(string(body))
+	result, err := doRequest[*PaginationResult[*Task]](ctx, h, &apiCall{
+		method:   http.MethodGet,
+		endpoint: endpointTasks,
+		query:    query.values(),
+	})
+	if err == nil && result == nil {
+		err = errEmptyResult(endpointTasks)
 	}
+	if err != nil {
+		recordError(span, err)
+		h.logger.ErrorContext(ctx, "load tasks failed",
+			slog.String("task_id", query.ID),
+			slog.String("md5", query.MD5),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("zkwasm.tasks.count", len(result.Data)))
+	h.logger.DebugContext(ctx, "loaded tasks",
+		slog.String("task_id", query.ID),
+		slog.String("md5", query.MD5),
+		slog.Int("count", len(result.Data)),
+		slog.Int64("total", result.Total),
+	)
+
+	return result, nil
+}
 
-	return result.Result, nil
+// QueryTask returns the task with the given id, or ErrTaskNotFound.
+func (h *ZkWasmServiceHelper) QueryTask(ctx context.Context, id string) (*Task, error) {
+	result, err := h.LoadTasks(ctx, &TaskQueryParams{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range result.Data {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
 }
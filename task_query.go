@@ -0,0 +1,130 @@
+package zkwasm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidTaskQuery = errors.New("invalid task query")
+
+// Validate checks the query before it is sent.
+func (q *TaskQueryParams) Validate() error {
+	if q.UserAddress != "" && !common.IsHexAddress(q.UserAddress) {
+		return fmt.Errorf("%w: user address %q is not a hex address", ErrInvalidTaskQuery, q.UserAddress)
+	}
+	if q.Start < 0 {
+		return fmt.Errorf("%w: negative start %d", ErrInvalidTaskQuery, q.Start)
+	}
+	if q.Total < 0 {
+		return fmt.Errorf("%w: negative total %d", ErrInvalidTaskQuery, q.Total)
+	}
+	if !q.SubmitAfter.IsZero() && !q.SubmitBefore.IsZero() && !q.SubmitAfter.Before(q.SubmitBefore) {
+		return fmt.Errorf("%w: submit time range %s - %s is empty", ErrInvalidTaskQuery,
+			q.SubmitAfter.Format(time.RFC3339), q.SubmitBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (q *TaskQueryParams) values() url.Values {
+	v := url.Values{}
+	if q.UserAddress != "" {
+		v.Add("user_address", q.UserAddress)
+	}
+	if q.ID != "" {
+		v.Add("id", q.ID)
+	}
+	if q.MD5 != "" {
+		v.Add("md5", q.MD5)
+	}
+	if q.TaskType != "" {
+		v.Add("tasktype", q.TaskType)
+	}
+	if q.TaskStatus != "" {
+		v.Add("taskstatus", string(q.TaskStatus))
+	}
+	if !q.SubmitAfter.IsZero() {
+		v.Add("submit_after", q.SubmitAfter.UTC().Format(time.RFC3339))
+	}
+	if !q.SubmitBefore.IsZero() {
+		v.Add("submit_before", q.SubmitBefore.UTC().Format(time.RFC3339))
+	}
+	if q.Start != 0 {
+		v.Add("start", strconv.FormatInt(q.Start, 10))
+	}
+	if q.Total != 0 {
+		v.Add("total", strconv.FormatInt(q.Total, 10))
+	}
+	return v
+}
+
+// TaskQuery builds a TaskQueryParams, e.g.
+//
+//	NewTaskQuery().ForUser(addr).WithStatus(TaskStatusDone).Since(t).Limit(50).Build()
+type TaskQuery struct {
+	params TaskQueryParams
+}
+
+func NewTaskQuery() *TaskQuery {
+	return &TaskQuery{}
+}
+
+func (b *TaskQuery) ForUser(address string) *TaskQuery {
+	b.params.UserAddress = address
+	return b
+}
+
+func (b *TaskQuery) ForImage(md5 string) *TaskQuery {
+	b.params.MD5 = md5
+	return b
+}
+
+func (b *TaskQuery) WithID(id string) *TaskQuery {
+	b.params.ID = id
+	return b
+}
+
+func (b *TaskQuery) WithType(taskType string) *TaskQuery {
+	b.params.TaskType = taskType
+	return b
+}
+
+func (b *TaskQuery) WithStatus(status TaskStatus) *TaskQuery {
+	b.params.TaskStatus = status
+	return b
+}
+
+// Since limits the query to tasks submitted at or after t.
+func (b *TaskQuery) Since(t time.Time) *TaskQuery {
+	b.params.SubmitAfter = t
+	return b
+}
+
+// Until limits the query to tasks submitted before t.
+func (b *TaskQuery) Until(t time.Time) *TaskQuery {
+	b.params.SubmitBefore = t
+	return b
+}
+
+func (b *TaskQuery) Offset(start int64) *TaskQuery {
+	b.params.Start = start
+	return b
+}
+
+func (b *TaskQuery) Limit(total int64) *TaskQuery {
+	b.params.Total = total
+	return b
+}
+
+// Build validates the query and returns a copy of its params.
+func (b *TaskQuery) Build() (*TaskQueryParams, error) {
+	params := b.params
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
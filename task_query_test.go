@@ -0,0 +1,81 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTaskQueryBuild(t *testing.T) {
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		query   *TaskQuery
+		wantErr bool
+	}{
+		{"empty", NewTaskQuery(), false},
+		{"full", NewTaskQuery().ForUser("0x582867abf63EbfA5803327a960AC381C2E01d67b").WithStatus(TaskStatusDone).Since(since).Until(since.Add(time.Hour)).Offset(10).Limit(50), false},
+		{"bad user", NewTaskQuery().ForUser("alice"), true},
+		{"negative offset", NewTaskQuery().Offset(-1), true},
+		{"negative limit", NewTaskQuery().Limit(-1), true},
+		{"since after until", NewTaskQuery().Since(since).Until(since.Add(-time.Hour)), true},
+		{"empty range", NewTaskQuery().Since(since).Until(since), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := tt.query.Build()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTaskQuery) {
+					t.Errorf("Build() err = %v, want %v", err, ErrInvalidTaskQuery)
+				}
+				return
+			}
+			if err != nil || params == nil {
+				t.Errorf("Build() = %v, %v", params, err)
+			}
+		})
+	}
+}
+
+func TestLoadTasksQuery(t *testing.T) {
+	var got url.Values
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	})
+
+	since := time.Date(2024, 5, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	params, err := NewTaskQuery().
+		ForUser("0x582867abf63EbfA5803327a960AC381C2E01d67b").
+		ForImage("md5").
+		WithStatus(TaskStatusDone).
+		Since(since).
+		Offset(20).
+		Limit(10).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.LoadTasks(context.Background(), params); err != nil {
+		t.Fatalf("LoadTasks() err = %v", err)
+	}
+
+	want := url.Values{
+		"user_address": {"0x582867abf63EbfA5803327a960AC381C2E01d67b"},
+		"md5":          {"md5"},
+		"taskstatus":   {string(TaskStatusDone)},
+		"submit_after": {"2024-05-01T00:00:00Z"},
+		"start":        {"20"},
+		"total":        {"10"},
+	}
+	if got.Encode() != want.Encode() {
+		t.Errorf("query = %s, want %s", got.Encode(), want.Encode())
+	}
+
+	if _, err := h.LoadTasks(context.Background(), &TaskQueryParams{Start: -1}); !errors.Is(err, ErrInvalidTaskQuery) {
+		t.Errorf("LoadTasks() err = %v, want %v", err, ErrInvalidTaskQuery)
+	}
+}
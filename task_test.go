@@ -0,0 +1,114 @@
+package zkwasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTaskTimestamps(t *testing.T) {
+	submitted := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                    string
+		json                    string
+		queue, proving, total   time.Duration
+		wantStarted, wantFinish bool
+	}{
+		{
+			name:        "finished",
+			json:        `{"submit_time":"2024-05-01T08:00:00Z","process_started":"2024-05-01T08:00:30.5Z","process_finished":"2024-05-01T08:02:00Z"}`,
+			queue:       30500 * time.Millisecond,
+			proving:     89500 * time.Millisecond,
+			total:       2 * time.Minute,
+			wantStarted: true,
+			wantFinish:  true,
+		},
+		{
+			name:        "processing",
+			json:        `{"submit_time":"2024-05-01T08:00:00.000","process_started":"2024-05-01T10:00:10+02:00","process_finished":null}`,
+			queue:       10 * time.Second,
+			wantStarted: true,
+		},
+		{
+			name: "pending",
+			json: `{"submit_time":1714550400000,"process_started":""}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var task Task
+			if err := json.Unmarshal([]byte(tt.json), &task); err != nil {
+				t.Fatal(err)
+			}
+			if !task.SubmitTime.Equal(submitted) {
+				t.Errorf("SubmitTime = %s, want %s", task.SubmitTime, submitted)
+			}
+			if (task.ProcessStarted != nil) != tt.wantStarted || (task.ProcessFinished != nil) != tt.wantFinish {
+				t.Errorf("ProcessStarted = %v, ProcessFinished = %v", task.ProcessStarted, task.ProcessFinished)
+			}
+			if got := task.QueueDuration(); got != tt.queue {
+				t.Errorf("QueueDuration() = %s, want %s", got, tt.queue)
+			}
+			if got := task.ProvingDuration(); got != tt.proving {
+				t.Errorf("ProvingDuration() = %s, want %s", got, tt.proving)
+			}
+			if got := task.TotalDuration(); got != tt.total {
+				t.Errorf("TotalDuration() = %s, want %s", got, tt.total)
+			}
+		})
+	}
+}
+
+func TestTaskUnmarshalInvalidTimestamp(t *testing.T) {
+	var task Task
+	if err := json.Unmarshal([]byte(`{"id":"1","submit_time":"yesterday"}`), &task); err == nil {
+		t.Errorf("Unmarshal() err = nil for an invalid submit_time")
+	}
+}
+
+func TestLoadTasksVerificationData(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"result":{"total":1,"data":[{
+			"id": "1",
+			"status": "Done",
+			"task_verification_data": {
+				"static_file_checksum": "AQID",
+				"verifier_contracts": {"chain_id": 11155111, "aggregator_verifier": "0x9D48Dce80682108864F1FB719229DCd0C45E51D7", "circuit_size": 22}
+			},
+			"batch_proof_data": {
+				"round_1_info": {"batch_id": "b1", "circuit_size": 22, "index": 3, "task_ids": ["1", "2"], "proof": "BAU="},
+				"final_proof_submit_info": {"batch_id": "b2", "index": 0, "chain_id": 11155111, "tx_hash": ["0xabc"]}
+			}
+		}]}}`))
+	})
+
+	result, err := h.LoadTasks(context.Background(), &TaskQueryParams{ID: "1"})
+	if err != nil || len(result.Data) != 1 {
+		t.Fatalf("LoadTasks() = %v, %v", result, err)
+	}
+	task := result.Data[0]
+
+	v := task.TaskVerificationData
+	if v == nil || !bytes.Equal(v.StaticFileChecksum, []byte{1, 2, 3}) || v.VerifierContracts.CircuitSize != 22 {
+		t.Fatalf("TaskVerificationData = %+v", v)
+	}
+	if addr, ok := v.VerifierContract(11155111); !ok || addr != common.HexToAddress("0x9D48Dce80682108864F1FB719229DCd0C45E51D7") {
+		t.Errorf("VerifierContract(11155111) = %s, %v", addr, ok)
+	}
+	if _, ok := v.VerifierContract(1); ok {
+		t.Errorf("VerifierContract(1) found a contract")
+	}
+
+	b := task.BatchProofData
+	if b == nil || b.Round1 == nil || b.Round1.Index != 3 || !bytes.Equal(b.Round1.Proof, []byte{4, 5}) || b.Round2 != nil {
+		t.Fatalf("BatchProofData = %+v", b)
+	}
+	if !b.IsSubmitted() {
+		t.Errorf("IsSubmitted() = false")
+	}
+}
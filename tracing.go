@@ -0,0 +1,72 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/zkcrossteam/zkWasm-service-helper"
+
+const (
+	attrImageMD5 = attribute.Key("zkwasm.image.md5")
+	attrTaskID   = attribute.Key("zkwasm.task.id")
+	attrEndpoint = attribute.Key("zkwasm.endpoint")
+	attrAttempt  = attribute.Key("zkwasm.attempt")
+	attrChainID  = attribute.Key("eth.chain_id")
+	attrTxHash   = attribute.Key("eth.tx_hash")
+)
+
+func (h *ZkWasmServiceHelper) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return h.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// traceMiddleware creates a client span for every attempt of a call and
+// propagates the trace context in the request headers.
+func (h *ZkWasmServiceHelper) traceMiddleware(next RoundTripFunc) RoundTripFunc {
+	return func(call *Call) (*CallResult, error) {
+		ctx, span := h.tracer.Start(call.Request.Context(), call.Request.Method+" "+call.Endpoint,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(call.Request.Method),
+				semconv.URLFull(call.Request.URL.String()),
+				semconv.ServerAddress(call.Request.URL.Hostname()),
+				attrEndpoint.String(call.Endpoint),
+				attrAttempt.Int(call.Attempt),
+			),
+		)
+		defer span.End()
+
+		call.Request = call.Request.WithContext(ctx)
+		h.propagator.Inject(ctx, propagation.HeaderCarrier(call.Request.Header))
+
+		result, err := next(call)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				span.SetAttributes(semconv.HTTPResponseStatusCode(apiErr.StatusCode))
+			}
+			recordError(span, err)
+			return result, err
+		}
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(result.StatusCode))
+		if result.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, strconv.Itoa(result.StatusCode))
+		}
+
+		return result, nil
+	}
+}
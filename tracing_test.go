@@ -0,0 +1,67 @@
+package zkwasm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spanAttr(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var traceparent string
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"success":true,"result":{"md5":"image-md5","id":"task-id"}}`))
+	}, WithTracerProvider(tp), WithPropagator(propagation.TraceContext{}))
+
+	_, err := h.AddProvingTask(context.Background(), &ProvingParams{
+		UserAddress: h.GetUserAddress(),
+		MD5:         "image-md5",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	httpSpan, methodSpan := spans[0], spans[1]
+	if methodSpan.Name != "zkwasm.AddProvingTask" || httpSpan.Name != "POST /prove" {
+		t.Fatalf("span names = %q, %q", methodSpan.Name, httpSpan.Name)
+	}
+	if httpSpan.Parent.SpanID() != methodSpan.SpanContext.SpanID() {
+		t.Errorf("http span is not a child of the method span")
+	}
+	if got := spanAttr(methodSpan, attrImageMD5).AsString(); got != "image-md5" {
+		t.Errorf("%s = %q, want %q", attrImageMD5, got, "image-md5")
+	}
+	if got := spanAttr(methodSpan, attrTaskID).AsString(); got != "task-id" {
+		t.Errorf("%s = %q, want %q", attrTaskID, got, "task-id")
+	}
+	if got := spanAttr(httpSpan, "http.response.status_code").AsInt64(); got != http.StatusOK {
+		t.Errorf("http.response.status_code = %d, want %d", got, http.StatusOK)
+	}
+
+	want := "00-" + httpSpan.SpanContext.TraceID().String() + "-" + httpSpan.SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("traceparent = %q, want %q", traceparent, want)
+	}
+}
@@ -0,0 +1,61 @@
+package zkwasm
+
+import "github.com/ethereum/go-ethereum/common"
+
+// TaskVerificationData describes how a finished task is verified on-chain.
+type TaskVerificationData struct {
+	// StaticFileChecksum is the checksum of the circuit's static files the
+	// proof was generated with.
+	StaticFileChecksum []byte            `json:"static_file_checksum"`
+	VerifierContracts  VerifierContracts `json:"verifier_contracts"`
+}
+
+type VerifierContracts struct {
+	ChainID            int64  `json:"chain_id"`
+	AggregatorVerifier string `json:"aggregator_verifier"`
+	CircuitSize        int64  `json:"circuit_size"`
+}
+
+// VerifierContract returns the aggregator verifier for chainID, if the task
+// was proven for that chain.
+func (d *TaskVerificationData) VerifierContract(chainID int64) (common.Address, bool) {
+	c := d.VerifierContracts
+	if c.ChainID != chainID || !common.IsHexAddress(c.AggregatorVerifier) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(c.AggregatorVerifier), true
+}
+
+// BatchProofData tracks a task through proof batching. Rounds are nil until
+// the task has been batched in them.
+type BatchProofData struct {
+	Round1           *BatchRound       `json:"round_1_info,omitempty"`
+	Round2           *BatchRound       `json:"round_2_info,omitempty"`
+	FinalProofSubmit *FinalProofSubmit `json:"final_proof_submit_info,omitempty"`
+}
+
+// BatchRound is one batching round and the proof it produced.
+type BatchRound struct {
+	BatchID     string `json:"batch_id"`
+	CircuitSize int64  `json:"circuit_size"`
+	// Index is the position of the task's proof in the batch.
+	Index           int64    `json:"index"`
+	TaskIDs         []string `json:"task_ids,omitempty"`
+	Proof           []byte   `json:"proof,omitempty"`
+	Instances       []byte   `json:"instances,omitempty"`
+	ShadowInstances []byte   `json:"shadow_instances,omitempty"`
+	Aux             []byte   `json:"aux,omitempty"`
+}
+
+// FinalProofSubmit records the submission of the final batch proof.
+type FinalProofSubmit struct {
+	BatchID string   `json:"batch_id"`
+	Index   int64    `json:"index"`
+	ChainID int64    `json:"chain_id,omitempty"`
+	TxHash  []string `json:"tx_hash,omitempty"`
+}
+
+// IsSubmitted reports whether the final batch proof was submitted on-chain.
+func (d *BatchProofData) IsSubmitted() bool {
+	return d.FinalProofSubmit != nil && len(d.FinalProofSubmit.TxHash) > 0
+}
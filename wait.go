@@ -0,0 +1,121 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+var ErrTaskFailed = errors.New("task failed")
+
+// TaskFailedError is returned by WaitForTask when the task ends in Fail,
+// DryRunFailed or Stale. It matches ErrTaskFailed with errors.Is.
+type TaskFailedError struct {
+	ID            string
+	Status        TaskStatus
+	StatusMessage string
+	Task          *Task
+}
+
+func (e *TaskFailedError) Error() string {
+	if e.StatusMessage == "" {
+		return fmt.Sprintf("task %s: %s", e.ID, e.Status)
+	}
+	return fmt.Sprintf("task %s: %s: %s", e.ID, e.Status, e.StatusMessage)
+}
+
+func (e *TaskFailedError) Is(target error) bool {
+	return target == ErrTaskFailed
+}
+
+// WaitOptions controls how WaitForTask polls. Zero fields take the values
+// of DefaultWaitOptions.
+type WaitOptions struct {
+	// Interval is the delay before the second poll.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls.
+	MaxInterval time.Duration
+	// Multiplier grows the delay after each poll without a status change.
+	Multiplier float64
+
+	// OnTransition is called whenever the status changes, including the
+	// first poll, where from is "".
+	OnTransition func(from, to TaskStatus, task *Task)
+}
+
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Interval:    5 * time.Second,
+		MaxInterval: time.Minute,
+		Multiplier:  1.5,
+	}
+}
+
+func (o *WaitOptions) withDefaults() WaitOptions {
+	opts := DefaultWaitOptions()
+	if o == nil {
+		return opts
+	}
+	if o.Interval > 0 {
+		opts.Interval = o.Interval
+	}
+	if o.MaxInterval > 0 {
+		opts.MaxInterval = o.MaxInterval
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	if o.Multiplier >= 1 {
+		opts.Multiplier = o.Multiplier
+	}
+	opts.OnTransition = o.OnTransition
+	return opts
+}
+
+// WaitForTask polls the task until it is Done, Fail, DryRunFailed or Stale.
+// It returns the task when it is Done and a *TaskFailedError otherwise.
+// Other errors are returned with the last task polled, if any.
+// The delay between polls grows while the status is unchanged and is reset
+// on every transition.
+func (h *ZkWasmServiceHelper) WaitForTask(ctx context.Context, id string, opts *WaitOptions) (*Task, error) {
+	o := opts.withDefaults()
+
+	var last *Task
+	var status TaskStatus
+	interval := o.Interval
+	for {
+		task, err := h.QueryTask(ctx, id)
+		if err != nil {
+			return last, err
+		}
+		last = task
+
+		if task.Status != status {
+			h.logger.DebugContext(ctx, "task status changed",
+				slog.String("task_id", id),
+				slog.String("from", string(status)),
+				slog.String("to", string(task.Status)),
+			)
+			if o.OnTransition != nil {
+				o.OnTransition(status, task.Status, task)
+			}
+			status = task.Status
+			interval = o.Interval
+		} else {
+			interval = min(time.Duration(float64(interval)*o.Multiplier), o.MaxInterval)
+		}
+
+		if status.IsTerminal() {
+			if status.IsSuccess() {
+				return task, nil
+			}
+			return task, &TaskFailedError{ID: id, Status: status, StatusMessage: task.StatusMessage, Task: task}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return task, err
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package zkwasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serveTaskStatuses answers each poll with the next status, repeating the
+// last one.
+func serveTaskStatuses(statuses ...TaskStatus) http.HandlerFunc {
+	var mu sync.Mutex
+	var polls int
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := statuses[min(polls, len(statuses)-1)]
+		polls++
+		mu.Unlock()
+
+		task := &Task{ID: r.URL.Query().Get("id"), Status: status}
+		if status == TaskStatusFail {
+			task.StatusMessage = "out of memory"
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"success": true,
+			"result":  map[string]any{"data": []*Task{task}, "total": 1},
+		})
+	}
+}
+
+func TestWaitForTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []TaskStatus
+		transitions string
+		wantErr     bool
+	}{
+		{"done", []TaskStatus{TaskStatusPending, TaskStatusPending, TaskStatusProcessing, TaskStatusDone}, "[->Pending Pending->Processing Processing->Done]", false},
+		{"already done", []TaskStatus{TaskStatusDone}, "[->Done]", false},
+		{"fail", []TaskStatus{TaskStatusProcessing, TaskStatusFail}, "[->Processing Processing->Fail]", true},
+		{"dry run failed", []TaskStatus{TaskStatusDryRunFailed}, "[->DryRunFailed]", true},
+		{"stale", []TaskStatus{TaskStatusPending, TaskStatusStale}, "[->Pending Pending->Stale]", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHelper(t, serveTaskStatuses(tt.statuses...))
+
+			var transitions []string
+			task, err := h.WaitForTask(context.Background(), "task-1", &WaitOptions{
+				Interval: time.Millisecond,
+				OnTransition: func(from, to TaskStatus, task *Task) {
+					transitions = append(transitions, string(from)+"->"+string(to))
+				},
+			})
+			if got := fmt.Sprint(transitions); got != tt.transitions {
+				t.Errorf("transitions = %s, want %s", got, tt.transitions)
+			}
+			if task == nil || task.ID != "task-1" {
+				t.Errorf("task = %+v", task)
+			}
+
+			var failed *TaskFailedError
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("WaitForTask() err = %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &failed) || !errors.Is(err, ErrTaskFailed) {
+				t.Fatalf("WaitForTask() err = %v, want a *TaskFailedError", err)
+			}
+			if failed.Status != tt.statuses[len(tt.statuses)-1] || failed.StatusMessage != task.StatusMessage {
+				t.Errorf("err = %+v", failed)
+			}
+		})
+	}
+}
+
+func TestWaitForTaskCancel(t *testing.T) {
+	serve := serveTaskStatuses(TaskStatusProcessing)
+	polled := make(chan struct{}, 10)
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r)
+		select {
+		case polled <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// The second poll is only sent once the first response was handled.
+		<-polled
+		<-polled
+		cancel()
+	}()
+
+	task, err := h.WaitForTask(ctx, "task-1", &WaitOptions{Interval: time.Millisecond})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForTask() err = %v, want %v", err, context.Canceled)
+	}
+	if task == nil || task.Status != TaskStatusProcessing {
+		t.Errorf("task = %+v, want the last polled task", task)
+	}
+}
+
+func TestWaitForTaskNotFound(t *testing.T) {
+	h := newTestHelper(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"result":{"data":[],"total":0}}`))
+	})
+
+	if _, err := h.WaitForTask(context.Background(), "task-1", nil); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("WaitForTask() err = %v, want %v", err, ErrTaskNotFound)
+	}
+}
@@ -0,0 +1,278 @@
+package zkwasm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TaskEvent reports a status change of a watched task. OldStatus is empty
+// for the first status seen.
+type TaskEvent struct {
+	ID        string
+	OldStatus TaskStatus
+	NewStatus TaskStatus
+	Task      *Task
+}
+
+// WatcherOptions configures a TaskWatcher. Zero fields take the values of
+// DefaultWatcherOptions.
+type WatcherOptions struct {
+	// Interval is the delay between polls.
+	Interval time.Duration
+
+	// UserAddress scopes the batched query. It defaults to the helper's
+	// address; tasks of other users are found by per-ID lookups, which is
+	// the only way a read-only helper without UserAddress can poll.
+	UserAddress string
+	// PageSize and MaxPages bound the batched query. Watched tasks not in
+	// the first MaxPages pages are looked up one by one.
+	PageSize int64
+	MaxPages int
+	// MaxLookups caps the per-ID lookups of each poll. Further tasks are
+	// looked up on the following polls, in turn, so each of them is polled
+	// at least every ceil(n/MaxLookups) intervals.
+	MaxLookups int
+
+	// Buffer is the capacity of the events channel.
+	Buffer int
+
+	// OnError is called when a poll fails. The watcher keeps polling.
+	OnError func(error)
+}
+
+func DefaultWatcherOptions() WatcherOptions {
+	return WatcherOptions{
+		Interval:   10 * time.Second,
+		PageSize:   DefaultPageSize,
+		MaxPages:   3,
+		MaxLookups: 10,
+		Buffer:     64,
+	}
+}
+
+// TaskWatcher follows many tasks with as few LoadTasks calls as possible
+// and delivers their status changes on Events. Tasks stop being watched
+// once they reach a terminal status or are not found.
+type TaskWatcher struct {
+	h    *ZkWasmServiceHelper
+	opts WatcherOptions
+
+	mu       sync.Mutex
+	statuses map[string]TaskStatus
+
+	// lastLookup is the last task looked up by ID, the next poll resumes
+	// after it. It is only used by the polling goroutine.
+	lastLookup string
+
+	events chan TaskEvent
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTaskWatcher starts a watcher which polls until ctx is done or Close
+// is called.
+func (h *ZkWasmServiceHelper) NewTaskWatcher(ctx context.Context, opts *WatcherOptions) *TaskWatcher {
+	o := DefaultWatcherOptions()
+	o.UserAddress = h.GetUserAddress()
+	if opts != nil {
+		if opts.Interval > 0 {
+			o.Interval = opts.Interval
+		}
+		if opts.UserAddress != "" {
+			o.UserAddress = opts.UserAddress
+		}
+		if opts.PageSize > 0 {
+			o.PageSize = opts.PageSize
+		}
+		if opts.MaxPages > 0 {
+			o.MaxPages = opts.MaxPages
+		}
+		if opts.MaxLookups > 0 {
+			o.MaxLookups = opts.MaxLookups
+		}
+		if opts.Buffer > 0 {
+			o.Buffer = opts.Buffer
+		}
+		o.OnError = opts.OnError
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &TaskWatcher{
+		h:        h,
+		opts:     o,
+		statuses: map[string]TaskStatus{},
+		events:   make(chan TaskEvent, o.Buffer),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+// Events is closed when the watcher stops.
+func (w *TaskWatcher) Events() <-chan TaskEvent {
+	return w.events
+}
+
+// Add starts watching ids. Tasks already watched keep their last status.
+func (w *TaskWatcher) Add(ids ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := w.statuses[id]; !ok {
+			w.statuses[id] = ""
+		}
+	}
+}
+
+func (w *TaskWatcher) Remove(ids ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range ids {
+		delete(w.statuses, id)
+	}
+}
+
+// Len returns the number of watched tasks.
+func (w *TaskWatcher) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.statuses)
+}
+
+// Close stops the watcher and waits for the events channel to be closed.
+func (w *TaskWatcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *TaskWatcher) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		w.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *TaskWatcher) poll(ctx context.Context) {
+	w.mu.Lock()
+	pending := make(map[string]bool, len(w.statuses))
+	for id := range w.statuses {
+		pending[id] = true
+	}
+	w.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	if w.opts.UserAddress != "" {
+		query := &TaskQueryParams{UserAddress: w.opts.UserAddress, Total: w.opts.PageSize}
+		for page := 0; page < w.opts.MaxPages && len(pending) > 0; page++ {
+			query.Start = int64(page) * w.opts.PageSize
+			result, err := w.h.LoadTasks(ctx, query)
+			if err != nil {
+				w.fail(ctx, err)
+				break
+			}
+			for _, task := range result.Data {
+				if pending[task.ID] {
+					delete(pending, task.ID)
+					if !w.update(ctx, task) {
+						return
+					}
+				}
+			}
+			if int64(len(result.Data)) < w.opts.PageSize || query.Start+w.opts.PageSize >= result.Total {
+				break
+			}
+		}
+	}
+
+	for _, id := range w.nextLookups(pending) {
+		if ctx.Err() != nil {
+			return
+		}
+		task, err := w.h.QueryTask(ctx, id)
+		if err != nil {
+			w.fail(ctx, err)
+			if errors.Is(err, ErrTaskNotFound) {
+				w.Remove(id)
+			}
+			continue
+		}
+		if !w.update(ctx, task) {
+			return
+		}
+	}
+}
+
+// nextLookups returns up to MaxLookups of the pending tasks, in ID order,
+// starting after the last task looked up on the previous poll.
+func (w *TaskWatcher) nextLookups(pending map[string]bool) []string {
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	slices.Sort(ids)
+
+	start, found := slices.BinarySearch(ids, w.lastLookup)
+	if found {
+		start++
+	}
+	n := min(w.opts.MaxLookups, len(ids))
+	lookups := make([]string, n)
+	for i := range lookups {
+		lookups[i] = ids[(start+i)%len(ids)]
+	}
+	w.lastLookup = lookups[n-1]
+	return lookups
+}
+
+// update records the status of task and sends an event when it changed.
+// It returns false when ctx is done.
+func (w *TaskWatcher) update(ctx context.Context, task *Task) bool {
+	w.mu.Lock()
+	old, ok := w.statuses[task.ID]
+	if !ok || old == task.Status {
+		w.mu.Unlock()
+		return true
+	}
+	if task.Status.IsTerminal() {
+		delete(w.statuses, task.ID)
+	} else {
+		w.statuses[task.ID] = task.Status
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.events <- TaskEvent{ID: task.ID, OldStatus: old, NewStatus: task.Status, Task: task}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *TaskWatcher) fail(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	w.h.logger.WarnContext(ctx, "task watcher poll failed", slog.Any("error", err))
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+	}
+}
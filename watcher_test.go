@@ -0,0 +1,176 @@
+package zkwasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeHub struct {
+	mu       sync.Mutex
+	tasks    map[string]*Task
+	owned    []string
+	scoped   int
+	lookedUp map[string]bool
+	// perPoll counts the lookups after each scoped query.
+	perPoll []int
+}
+
+func (f *fakeHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var data []*Task
+	if id := r.URL.Query().Get("id"); id != "" {
+		f.lookedUp[id] = true
+		if len(f.perPoll) > 0 {
+			f.perPoll[len(f.perPoll)-1]++
+		}
+		if task, ok := f.tasks[id]; ok {
+			data = append(data, task)
+		}
+	} else {
+		f.scoped++
+		f.perPoll = append(f.perPoll, 0)
+		for _, id := range f.owned {
+			data = append(data, f.tasks[id])
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"success": true,
+		"result":  map[string]any{"data": data, "total": len(data)},
+	})
+}
+
+func (f *fakeHub) set(id string, status TaskStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tasks[id] = &Task{ID: id, Status: status}
+}
+
+func nextEvent(t *testing.T, events <-chan TaskEvent) TaskEvent {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("events channel closed")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+	return TaskEvent{}
+}
+
+func TestTaskWatcher(t *testing.T) {
+	hub := &fakeHub{tasks: map[string]*Task{}, lookedUp: map[string]bool{}, owned: []string{"a", "b"}}
+	hub.set("a", TaskStatusPending)
+	hub.set("b", TaskStatusProcessing)
+	hub.set("c", TaskStatusPending)
+	h := newTestHelper(t, hub.ServeHTTP)
+
+	w := h.NewTaskWatcher(context.Background(), &WatcherOptions{Interval: 20 * time.Millisecond})
+	defer w.Close()
+	w.Add("a", "b", "c")
+
+	var first []string
+	for i := 0; i < 3; i++ {
+		e := nextEvent(t, w.Events())
+		if e.OldStatus != "" || e.Task == nil || e.Task.Status != e.NewStatus {
+			t.Errorf("event = %+v", e)
+		}
+		first = append(first, e.ID+":"+string(e.NewStatus))
+	}
+	sort.Strings(first)
+	if want := []string{"a:Pending", "b:Processing", "c:Pending"}; !slices.Equal(first, want) {
+		t.Errorf("first events = %v, want %v", first, want)
+	}
+
+	hub.mu.Lock()
+	if hub.scoped == 0 || len(hub.lookedUp) != 1 || !hub.lookedUp["c"] {
+		t.Errorf("%d scoped queries, looked up %v, want only c looked up", hub.scoped, hub.lookedUp)
+	}
+	hub.mu.Unlock()
+
+	hub.set("a", TaskStatusDone)
+	e := nextEvent(t, w.Events())
+	if e.ID != "a" || e.OldStatus != TaskStatusPending || e.NewStatus != TaskStatusDone {
+		t.Errorf("event = %+v, want a Pending->Done", e)
+	}
+	if w.Len() != 2 {
+		t.Errorf("Len() = %d after a finished, want 2", w.Len())
+	}
+
+	w.Remove("b")
+	hub.set("b", TaskStatusFail)
+	hub.set("c", TaskStatusFail)
+	e = nextEvent(t, w.Events())
+	if e.ID != "c" || e.NewStatus != TaskStatusFail {
+		t.Errorf("event = %+v, want c Fail", e)
+	}
+	if w.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", w.Len())
+	}
+}
+
+func TestTaskWatcherNotFound(t *testing.T) {
+	hub := &fakeHub{tasks: map[string]*Task{}, lookedUp: map[string]bool{}}
+	h := newTestHelper(t, hub.ServeHTTP)
+
+	errs := make(chan error, 1)
+	w := h.NewTaskWatcher(context.Background(), &WatcherOptions{
+		Interval: 10 * time.Millisecond,
+		OnError:  func(err error) { errs <- err },
+	})
+	w.Add("missing")
+
+	if err := <-errs; !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("OnError(%v), want %v", err, ErrTaskNotFound)
+	}
+	w.Close()
+	if w.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", w.Len())
+	}
+	if _, ok := <-w.Events(); ok {
+		t.Errorf("events channel not closed after Close")
+	}
+}
+
+func TestTaskWatcherMaxLookups(t *testing.T) {
+	hub := &fakeHub{tasks: map[string]*Task{}, lookedUp: map[string]bool{}}
+	ids := []string{"a", "b", "c", "d", "e"}
+	for _, id := range ids {
+		hub.set(id, TaskStatusProcessing)
+	}
+	h := newTestHelper(t, hub.ServeHTTP)
+
+	w := h.NewTaskWatcher(context.Background(), &WatcherOptions{Interval: 5 * time.Millisecond, MaxLookups: 2})
+	defer w.Close()
+	w.Add(ids...)
+
+	var got []string
+	for len(got) < len(ids) {
+		got = append(got, nextEvent(t, w.Events()).ID)
+	}
+	sort.Strings(got)
+	if !slices.Equal(got, ids) {
+		t.Errorf("events for %v, want %v", got, ids)
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for i, n := range hub.perPoll {
+		if n > 2 {
+			t.Errorf("poll %d looked up %d tasks, want at most 2", i, n)
+		}
+	}
+	if len(hub.perPoll) < 3 {
+		t.Errorf("%d polls, want at least 3 to look up 5 tasks", len(hub.perPoll))
+	}
+}